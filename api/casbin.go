@@ -0,0 +1,47 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"myproject/admin-api-gateway/config"
+	"myproject/admin-api-gateway/pkg/db"
+
+	"github.com/casbin/casbin/v2"
+	"github.com/casbin/casbin/v2/util"
+	gormadapter "github.com/casbin/gorm-adapter/v3"
+)
+
+// NewEnforcer loads the casbin model and the policies stored in postgres.
+// The returned function closes the policy adapter.
+func NewEnforcer(cfg config.Config) (*casbin.Enforcer, func() error, error) {
+	adapter, err := gormadapter.NewAdapter("postgres", db.PostgresDSN(cfg), true)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	casbinEnforcer, err := casbin.NewEnforcer(cfg.AuthConfigPath, adapter)
+	if err != nil {
+		adapter.Close()
+		return nil, nil, err
+	}
+
+	casbinEnforcer.GetRoleManager().AddMatchingFunc("keyMatch", util.KeyMatch)
+	casbinEnforcer.GetRoleManager().AddMatchingFunc("keyMatch3", util.KeyMatch3)
+
+	return casbinEnforcer, adapter.Close, nil
+}
+
+// EnforcerCheck reports whether the enforcer has its model and policies loaded
+func EnforcerCheck(casbinEnforcer *casbin.Enforcer) func(context.Context) error {
+	return func(context.Context) error {
+		if casbinEnforcer == nil || casbinEnforcer.GetModel() == nil {
+			return errors.New("casbin enforcer is not loaded")
+		}
+
+		if len(casbinEnforcer.GetPolicy()) == 0 {
+			return errors.New("casbin enforcer has no policies")
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,132 @@
+package health
+
+import (
+	"context"
+	"myproject/admin-api-gateway/api/models"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	statusUp   = "up"
+	statusDown = "down"
+)
+
+// CheckFunc reports an error when a dependency cannot serve requests
+type CheckFunc func(ctx context.Context) error
+
+type check struct {
+	name  string
+	check CheckFunc
+}
+
+type Handler struct {
+	ready   atomic.Bool
+	timeout time.Duration
+
+	mu     sync.RWMutex
+	checks []check
+}
+
+func New(timeout time.Duration) *Handler {
+	return &Handler{timeout: timeout}
+}
+
+// SetReady flips readiness, the gateway is not ready until startup completes
+// and stops being ready as soon as shutdown begins
+func (h *Handler) SetReady(ready bool) {
+	h.ready.Store(ready)
+}
+
+// AddCheck registers a dependency that must be healthy for the gateway to be ready
+func (h *Handler) AddCheck(name string, checkFunc CheckFunc) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.checks = append(h.checks, check{name: name, check: checkFunc})
+}
+
+// Live
+// @Router /healthz [get]
+// @Summary liveness probe
+// @Tags Health
+// @Description Reports whether the gateway process is running, dependencies are not checked
+// @Produce json
+// @Success 200 {object} models.HealthReport
+func (h *Handler) Live(c *gin.Context) {
+	c.JSON(http.StatusOK, models.HealthReport{Status: statusUp})
+}
+
+// Ready
+// @Router /readyz [get]
+// @Summary readiness probe
+// @Tags Health
+// @Description Reports whether the gateway accepts traffic along with the state of every dependency
+// @Produce json
+// @Success 200 {object} models.HealthReport
+// @Failure 503 {object} models.HealthReport
+func (h *Handler) Ready(c *gin.Context) {
+	report := h.run(c.Request.Context())
+
+	if !h.ready.Load() {
+		report.Status = statusDown
+	}
+
+	if report.Status != statusUp {
+		c.JSON(http.StatusServiceUnavailable, report)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// run executes all checks concurrently, each bounded by the check timeout
+func (h *Handler) run(ctx context.Context) models.HealthReport {
+	h.mu.RLock()
+	checks := append([]check(nil), h.checks...)
+	h.mu.RUnlock()
+
+	var (
+		wg      sync.WaitGroup
+		results = make([]models.DependencyStatus, len(checks))
+	)
+	for i, c := range checks {
+		wg.Add(1)
+		go func(i int, c check) {
+			defer wg.Done()
+
+			ctx, cancel := context.WithTimeout(ctx, h.timeout)
+			defer cancel()
+
+			start := time.Now()
+			err := c.check(ctx)
+
+			results[i] = models.DependencyStatus{
+				Status:    statusUp,
+				LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+			}
+			if err != nil {
+				results[i].Status = statusDown
+				results[i].Error = err.Error()
+			}
+		}(i, c)
+	}
+	wg.Wait()
+
+	report := models.HealthReport{
+		Status:       statusUp,
+		Dependencies: make(map[string]models.DependencyStatus, len(checks)),
+	}
+	for i, c := range checks {
+		report.Dependencies[c.name] = results[i]
+		if results[i].Status != statusUp {
+			report.Status = statusDown
+		}
+	}
+
+	return report
+}
@@ -7,6 +7,13 @@ import (
 	"github.com/dgrijalva/jwt-go"
 )
 
+// Keys of the gin context the subject and role of the caller are cached
+// under once the bearer token has been parsed
+const (
+	SubjectKey = "sub"
+	RoleKey    = "role"
+)
+
 type JWTHandler struct {
 	Sub       string
 	Exp       string
@@ -68,7 +75,7 @@ func ExtractClaims(tokenStr string, signInKey []byte) (jwt.MapClaims, error) {
 		return signInKey, nil
 	})
 
-	if !token.Valid {
+	if token == nil || !token.Valid {
 		return nil, err
 	}
 
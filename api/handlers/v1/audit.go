@@ -0,0 +1,177 @@
+package v1
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"myproject/admin-api-gateway/api/models"
+	"myproject/admin-api-gateway/pkg/logger"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxAuditPageSize bounds the page of the JSON listing, exports are streamed instead
+const maxAuditPageSize = 500
+
+var auditCSVHeader = []string{"id", "created_at", "actor_id", "actor_role", "action", "resource_type", "resource_id",
+	"method", "route", "status", "ip", "user_agent", "request_id", "diff"}
+
+// List Audit Log
+// @Router /v1/admin/audit [get]
+// @Security BearerAuth
+// @Summary list audit log
+// @Tags Admin
+// @Description Lists the recorded mutating requests, newest first
+// @Produce json
+// @Param actor_id query string false "JWT subject of the actor"
+// @Param actor_role query string false "role of the actor"
+// @Param action query string false "create, update, delete"
+// @Param resource_type query string false "doctor, department, specialization, specprice, user, admin, policy..."
+// @Param resource_id query string false "resource_id"
+// @Param from query string false "RFC3339 time, inclusive"
+// @Param to query string false "RFC3339 time, exclusive"
+// @Param page query int false "page"
+// @Param limit query int false "limit, at most 500"
+// @Success 200 {object} models.ListAuditEntries
+// @Failure 400 {object} models.ResponseError
+// @Failure 401 {object} models.ResponseError
+// @Failure 403 {object} models.ResponseError
+// @Failure 500 {object} models.ResponseError
+func (h *handlerV1) ListAuditLog(c *gin.Context) {
+	filter, err := parseAuditFilter(c)
+	if handleBadRequestErrWithMessage(c, h.log, err, ErrorCodeInvalidParams) {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), time.Second*time.Duration(h.cfg.CtxTimeout))
+	defer cancel()
+
+	response, err := h.auditStorage.List(ctx, filter)
+	if handleInternalServerErrorWithMessage(c, h.log, err, "failed to list audit log") {
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// Export Audit Log
+// @Router /v1/admin/audit/export [get]
+// @Security BearerAuth
+// @Summary export audit log
+// @Tags Admin
+// @Description Streams every entry matching the filters as CSV, newest first
+// @Produce text/csv
+// @Param actor_id query string false "JWT subject of the actor"
+// @Param actor_role query string false "role of the actor"
+// @Param action query string false "create, update, delete"
+// @Param resource_type query string false "resource_type"
+// @Param resource_id query string false "resource_id"
+// @Param from query string false "RFC3339 time, inclusive"
+// @Param to query string false "RFC3339 time, exclusive"
+// @Success 200 {string} string "CSV file"
+// @Failure 400 {object} models.ResponseError
+// @Failure 401 {object} models.ResponseError
+// @Failure 403 {object} models.ResponseError
+func (h *handlerV1) ExportAuditLog(c *gin.Context) {
+	filter, err := parseAuditFilter(c)
+	if handleBadRequestErrWithMessage(c, h.log, err, ErrorCodeInvalidParams) {
+		return
+	}
+
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="audit-%s.csv"`, time.Now().UTC().Format("20060102-150405")))
+	c.Status(http.StatusOK)
+
+	writer := csv.NewWriter(c.Writer)
+	_ = writer.Write(auditCSVHeader)
+
+	// the status is already sent, a failure can only cut the file short
+	err = h.auditStorage.Export(c.Request.Context(), filter, func(entry *models.AuditEntry) error {
+		diff := ""
+		if len(entry.Diff) > 0 {
+			raw, _ := json.Marshal(entry.Diff)
+			diff = string(raw)
+		}
+
+		record := []string{
+			strconv.FormatInt(entry.ID, 10),
+			entry.CreatedAt.UTC().Format(time.RFC3339),
+			entry.ActorID,
+			entry.ActorRole,
+			entry.Action,
+			entry.ResourceType,
+			entry.ResourceID,
+			entry.Method,
+			entry.Route,
+			strconv.Itoa(entry.Status),
+			entry.IP,
+			entry.UserAgent,
+			entry.RequestID,
+			diff,
+		}
+		for i := range record {
+			record[i] = csvSafe(record[i])
+		}
+
+		return writer.Write(record)
+	})
+	writer.Flush()
+	if err == nil {
+		err = writer.Error()
+	}
+	if err != nil {
+		logger.WithContext(h.log, c.Request.Context()).Error("audit export was interrupted", logger.Error(err))
+	}
+}
+
+func parseAuditFilter(c *gin.Context) (models.AuditFilter, error) {
+	page, err := ParsePageQueryParam(c)
+	if err != nil {
+		return models.AuditFilter{}, err
+	}
+	limit, err := ParseLimitQueryParam(c)
+	if err != nil {
+		return models.AuditFilter{}, err
+	}
+	if limit > maxAuditPageSize {
+		limit = maxAuditPageSize
+	}
+
+	filter := models.AuditFilter{
+		ActorID:      c.Query("actor_id"),
+		ActorRole:    c.Query("actor_role"),
+		Action:       c.Query("action"),
+		ResourceType: c.Query("resource_type"),
+		ResourceID:   c.Query("resource_id"),
+		Page:         page,
+		Limit:        limit,
+	}
+
+	for name, into := range map[string]*time.Time{"from": &filter.From, "to": &filter.To} {
+		value := c.Query(name)
+		if value == "" {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return models.AuditFilter{}, fmt.Errorf("%s should be an RFC3339 time, e.g. 2024-01-02T15:04:05Z", name)
+		}
+		*into = parsed
+	}
+
+	return filter, nil
+}
+
+// csvSafe keeps spreadsheet applications from evaluating a cell as a formula
+func csvSafe(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+
+	return value
+}
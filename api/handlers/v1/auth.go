@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"myproject/admin-api-gateway/api/handlers/tokens"
 	"myproject/admin-api-gateway/api/models"
+	"myproject/admin-api-gateway/pkg/audit"
 	"myproject/admin-api-gateway/pkg/etc"
 	"net/http"
 	"time"
@@ -25,9 +26,11 @@ import (
 // @Param username query string true "username"
 // @Param password query string true "password"
 // @Param admin body models.AdminReq true "admin"
+// @Param Idempotency-Key header string false "Unique key of the request, retries with the same key are replayed"
 // @Success 201 {object} models.SuperAdminMessage
-// @Failure 400 string error models.ResponseError
-// @Failure 400 string error models.ResponseError
+// @Failure 400 {object} models.ResponseError
+// @Failure 409 {object} models.ResponseError
+// @Failure 422 {object} models.ResponseError
 func (h *handlerV1) CreateAdmin(c *gin.Context) {
 	var (
 		jspbMarshal protojson.MarshalOptions
@@ -39,15 +42,15 @@ func (h *handlerV1) CreateAdmin(c *gin.Context) {
 	superAdminPassword := c.Query("password")
 
 	if superAdminUsername == "admin" && superAdminPassword == "admin" {
-		err := c.BindJSON(&body)
-		if handleBadRequestErrWithMessage(c, h.log, err, ErrorCodeInvalidJSON) {
+		err := c.ShouldBindJSON(&body)
+		if handleBindErr(c, h.log, err) {
 			return
 		}
 
 		body.Id = uuid.NewString()
 
 		body.Password, err = etc.GenerateHashPassword(body.Password)
-		if handleInternalServerErrorWithMessage(c, h.log, err, ErrorCodeInternalServerError) {
+		if handleInternalServerErrorWithMessage(c, h.log, err, "error while hashing the admin password") {
 			return
 		}
 
@@ -60,7 +63,7 @@ func (h *handlerV1) CreateAdmin(c *gin.Context) {
 		}
 
 		_, refresh, err := h.jwtHandler.GenerateAuthJWT()
-		if handleInternalServerErrorWithMessage(c, h.log, err, ErrorCodeInternalServerError) {
+		if handleInternalServerErrorWithMessage(c, h.log, err, "error while generating a refresh token for the admin") {
 			return
 		}
 
@@ -75,16 +78,18 @@ func (h *handlerV1) CreateAdmin(c *gin.Context) {
 			RefreshToken: refresh,
 		}
 
-		ctx, cancel := context.WithTimeout(context.Background(), time.Second*time.Duration((h.cfg.CtxTimeout)))
+		ctx, cancel := context.WithTimeout(c.Request.Context(), time.Second*time.Duration((h.cfg.CtxTimeout)))
 		defer cancel()
 
 		err = h.postgres.Create(ctx, &adminResp)
-		if handleInternalServerErrorWithMessage(c, h.log, err, ErrorCodeInternalServerError) {
+		if handleInternalServerErrorWithMessage(c, h.log, err, "error while creating admin") {
 			return
 		}
+		audit.SetResource(c.Request.Context(), "admin", adminResp.Id)
+		audit.SetAfter(c.Request.Context(), viewerOf(c).admin(&body))
 
 		c.JSON(http.StatusCreated, models.SuperAdminMessage{
-			Message: "admin successfully created",
+			Message: translate(c, "admin successfully created"),
 		})
 	} else {
 		if handleBadRequestErrWithMessage(c, h.log, fmt.Errorf("you cannot create admin, provide username and password"), ErrorCodeInvalidJSON) {
@@ -105,8 +110,7 @@ func (h *handlerV1) CreateAdmin(c *gin.Context) {
 // @Param password query string false "password"
 // @Param admin body models.DeleteAdmin true "admin"
 // @Success 201 {object} models.SuperAdminMessage
-// @Failure 400 string error models.ResponseError
-// @Failure 400 string error models.ResponseError
+// @Failure 400 {object} models.ResponseError
 func (h *handlerV1) DeleteAdmin(c *gin.Context) {
 	var (
 		jspbMarshal protojson.MarshalOptions
@@ -123,16 +127,16 @@ func (h *handlerV1) DeleteAdmin(c *gin.Context) {
 		}
 	}
 
-	err := c.BindJSON(&body)
-	if handleBadRequestErrWithMessage(c, h.log, err, ErrorCodeInvalidJSON) {
+	err := c.ShouldBindJSON(&body)
+	if handleBindErr(c, h.log, err) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second*time.Duration((h.cfg.CtxTimeout)))
+	ctx, cancel := context.WithTimeout(c.Request.Context(), time.Second*time.Duration((h.cfg.CtxTimeout)))
 	defer cancel()
 
 	_, password, status, err := h.postgres.Check(ctx, body.Username)
-	if handleInternalServerErrorWithMessage(c, h.log, err, ErrorCodeInternalServerError) {
+	if handleInternalServerErrorWithMessage(c, h.log, err, "error while checking admin credentials") {
 		return
 	}
 
@@ -155,13 +159,15 @@ func (h *handlerV1) DeleteAdmin(c *gin.Context) {
 				return
 			}
 		}
-		if handleInternalServerErrorWithMessage(c, h.log, err, ErrorCodeInternalServerError) {
+		if handleInternalServerErrorWithMessage(c, h.log, resp, "error while deleting admin") {
 			return
 		}
 	}
 
+	audit.SetResource(c.Request.Context(), "admin", body.Username)
+
 	c.JSON(http.StatusOK, models.SuperAdminMessage{
-		Message: "admin is successfully deleted",
+		Message: translate(c, "admin is successfully deleted"),
 	})
 }
 
@@ -174,8 +180,7 @@ func (h *handlerV1) DeleteAdmin(c *gin.Context) {
 // @Product json
 // @Param admin body models.AdminLoginReq true "Login"
 // @Success 201 {object} models.AdminLoginResp
-// @Failure 400 string error models.ResponseError
-// @Failure 400 string error models.ResponseError
+// @Failure 400 {object} models.ResponseError
 func (h *handlerV1) LoginAdmin(c *gin.Context) {
 	var (
 		jspbMarshal protojson.MarshalOptions
@@ -183,17 +188,17 @@ func (h *handlerV1) LoginAdmin(c *gin.Context) {
 	)
 	jspbMarshal.UseProtoNames = true
 
-	err := c.BindJSON(&body)
+	err := c.ShouldBindJSON(&body)
 
-	if handleBadRequestErrWithMessage(c, h.log, err, ErrorCodeInvalidJSON) {
+	if handleBindErr(c, h.log, err) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second*time.Duration((h.cfg.CtxTimeout)))
+	ctx, cancel := context.WithTimeout(c.Request.Context(), time.Second*time.Duration((h.cfg.CtxTimeout)))
 	defer cancel()
 
 	role, password, status, err := h.postgres.Check(ctx, body.Username)
-	if handleInternalServerErrorWithMessage(c, h.log, err, ErrorCodeInternalServerError) {
+	if handleInternalServerErrorWithMessage(c, h.log, err, "error while checking admin credentials") {
 		return
 	}
 
@@ -218,7 +223,6 @@ func (h *handlerV1) LoginAdmin(c *gin.Context) {
 			TimeOut:   h.cfg.AccessTokenTimeout,
 		}
 	} else if role == "superadmin" {
-		fmt.Println(role)
 		h.jwtHandler = tokens.JWTHandler{
 			Sub:       body.Username,
 			Role:      "superadmin",
@@ -229,7 +233,7 @@ func (h *handlerV1) LoginAdmin(c *gin.Context) {
 	}
 
 	access, _, err := h.jwtHandler.GenerateAuthJWT()
-	if handleInternalServerErrorWithMessage(c, h.log, err, ErrorCodeInternalServerError) {
+	if handleInternalServerErrorWithMessage(c, h.log, err, "error while generating an access token for the admin") {
 		return
 	}
 
@@ -251,9 +255,8 @@ func (h *handlerV1) LoginAdmin(c *gin.Context) {
 // @Product json
 // @Param page path string false "page"
 // @Param limit path string false "limit"
-// @Success 201 {object} models.ListAdminsResp
-// @Failure 400 string error models.ResponseError
-// @Failure 400 string error models.ResponseError
+// @Success 201 {object} models.ListAdmins
+// @Failure 400 {object} models.ResponseError
 func (h *handlerV1) ListAdmins(c *gin.Context) {
 	var jspbMarshal protojson.MarshalOptions
 	jspbMarshal.UseProtoNames = true
@@ -267,15 +270,15 @@ func (h *handlerV1) ListAdmins(c *gin.Context) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second*time.Duration((h.cfg.CtxTimeout)))
+	ctx, cancel := context.WithTimeout(c.Request.Context(), time.Second*time.Duration((h.cfg.CtxTimeout)))
 	defer cancel()
 
-	response, err := h.postgres.ListAdmins(ctx, models.ListAdminReq{Page: int32(page), Limit: int32(limit)})
-	if handleInternalServerErrorWithMessage(c, h.log, err, ErrorCodeInternalServerError) {
+	resp, err := h.postgres.ListAdmins(ctx, models.ListAdminReq{Page: int32(page), Limit: int32(limit)})
+	if handleInternalServerErrorWithMessage(c, h.log, err, "error while listing admins") {
 		return
 	}
 
-	c.JSON(http.StatusOK, response)
+	c.JSON(http.StatusOK, models.ListAdmins{Count: resp.Count, Admins: viewerOf(c).admins(resp.Admins)})
 }
 
 // Get Admin
@@ -287,24 +290,25 @@ func (h *handlerV1) ListAdmins(c *gin.Context) {
 // @Accept json
 // @Product json
 // @Param id path string true "id"
-// @Success 201 {object} models.AdminReq
-// @Failure 400 string error models.ResponseError
-// @Failure 400 string error models.ResponseError
+// @Param If-None-Match header string false "ETag of the version the client has"
+// @Success 201 {object} models.Admin
+// @Failure 400 {object} models.ResponseError
+// @Failure 404 {object} models.ResponseError
 func (h *handlerV1) GetAdmin(c *gin.Context) {
 	var jspMarshal protojson.MarshalOptions
 	jspMarshal.UseProtoNames = true
 
 	id := c.Param("id")
 
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second*time.Duration((h.cfg.CtxTimeout)))
+	ctx, cancel := context.WithTimeout(c.Request.Context(), time.Second*time.Duration((h.cfg.CtxTimeout)))
 	defer cancel()
 
 	respAdmin, err := h.postgres.GetAdmin(ctx, models.GetAdminReq{Id: id})
-	if handleInternalServerErrorWithMessage(c, h.log, err, ErrorCodeInternalServerError) {
+	if handlePostgresErrWithMessage(c, h.log, err, "error while getting admin by id") {
 		return
 	}
 
-	c.JSON(http.StatusOK, respAdmin)
+	writeWithETag(c, viewerOf(c).admin(respAdmin))
 }
 
 // Update Admin
@@ -316,9 +320,12 @@ func (h *handlerV1) GetAdmin(c *gin.Context) {
 // @Accept json
 // @Product json
 // @Param admin body models.AdminUpdateReq true "admin"
-// @Success 201 {object} models.AdminReq
-// @Failure 400 string error models.ResponseError
-// @Failure 400 string error models.ResponseError
+// @Param If-Match header string false "ETag of the version being updated"
+// @Success 201 {object} models.Admin
+// @Failure 400 {object} models.ResponseError
+// @Failure 404 {object} models.ResponseError
+// @Failure 412 {object} models.ResponseError
+// @Failure 428 {object} models.ResponseError
 func (h *handlerV1) Update(c *gin.Context) {
 	var (
 		body       models.AdminUpdateReq
@@ -327,16 +334,16 @@ func (h *handlerV1) Update(c *gin.Context) {
 
 	jspMarshal.UseProtoNames = true
 
-	err := c.BindJSON(&body)
-	if handleBadRequestErrWithMessage(c, h.log, err, ErrorCodeInvalidJSON) {
+	err := c.ShouldBindJSON(&body)
+	if handleBindErr(c, h.log, err) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second*time.Duration(h.cfg.CtxTimeout))
+	ctx, cancel := context.WithTimeout(c.Request.Context(), time.Second*time.Duration(h.cfg.CtxTimeout))
 	defer cancel()
 
 	_, _, status, err := h.postgres.Check(ctx, body.UserName)
-	if handleInternalServerErrorWithMessage(c, h.log, err, ErrorCodeInternalServerError) {
+	if handleInternalServerErrorWithMessage(c, h.log, err, "error while checking admin credentials") {
 		return
 	}
 
@@ -346,10 +353,22 @@ func (h *handlerV1) Update(c *gin.Context) {
 		}
 	}
 
+	if !h.checkIfMatch(c, func() (interface{}, error) {
+		admin, err := h.postgres.GetAdmin(ctx, models.GetAdminReq{Id: body.Id})
+		if err != nil {
+			return nil, err
+		}
+		return viewerOf(c).admin(admin), nil
+	}) {
+		return
+	}
+
 	response, err := h.postgres.Update(ctx, &body)
-	if handleInternalServerErrorWithMessage(c, h.log, err, "failed to update admin") {
+	if handlePostgresErrWithMessage(c, h.log, err, "failed to update admin") {
 		return
 	}
+	audit.SetResource(c.Request.Context(), "admin", response.Id)
+	audit.SetAfter(c.Request.Context(), response)
 
-	c.JSON(http.StatusOK, response)
+	c.JSON(http.StatusOK, viewerOf(c).admin(response))
 }
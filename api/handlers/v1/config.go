@@ -0,0 +1,79 @@
+package v1
+
+import (
+	"myproject/admin-api-gateway/api/models"
+	"myproject/admin-api-gateway/pkg/logger"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Get Config
+// @Router /v1/admin/config [get]
+// @Security BearerAuth
+// @Summary get running config
+// @Tags Admin
+// @Description Returns the config the gateway runs with, including reloaded changes. Secrets are masked
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} models.ResponseError
+// @Failure 403 {object} models.ResponseError
+func (h *handlerV1) GetConfig(c *gin.Context) {
+	c.JSON(http.StatusOK, h.config.Current().Redacted())
+}
+
+// Get Log Level
+// @Router /v1/admin/log-level [get]
+// @Security BearerAuth
+// @Summary get log level
+// @Tags Admin
+// @Description Returns the level the gateway currently logs at
+// @Produce json
+// @Success 200 {object} models.LogLevel
+// @Failure 401 {object} models.ResponseError
+// @Failure 403 {object} models.ResponseError
+func (h *handlerV1) GetLogLevel(c *gin.Context) {
+	c.JSON(http.StatusOK, models.LogLevel{
+		Level: logger.GetLevel(h.log),
+	})
+}
+
+// Update Log Level
+// @Router /v1/admin/log-level [put]
+// @Security BearerAuth
+// @Summary update log level
+// @Tags Admin
+// @Description Changes the log level until the next restart or until LOG_LEVEL is changed in the config file
+// @Accept json
+// @Produce json
+// @Param level body models.LogLevel true "level"
+// @Success 200 {object} models.LogLevel
+// @Failure 400 {object} models.ResponseError
+// @Failure 401 {object} models.ResponseError
+// @Failure 403 {object} models.ResponseError
+// @Failure 500 {object} models.ResponseError
+func (h *handlerV1) UpdateLogLevel(c *gin.Context) {
+	var body models.LogLevel
+
+	err := c.ShouldBindJSON(&body)
+	if handleBindErr(c, h.log, err) {
+		return
+	}
+
+	err = body.Validate()
+	if handleValidationErr(c, h.log, err) {
+		return
+	}
+
+	previous := logger.GetLevel(h.log)
+	err = logger.SetLevel(h.log, body.Level)
+	if handleInternalServerErrorWithMessage(c, h.log, err, "failed to change log level") {
+		return
+	}
+	logger.WithContext(h.log, c.Request.Context()).Warn("log level changed",
+		logger.String("from", previous), logger.String("to", body.Level))
+
+	c.JSON(http.StatusOK, models.LogLevel{
+		Level: logger.GetLevel(h.log),
+	})
+}
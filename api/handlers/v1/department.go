@@ -27,9 +27,12 @@ var FilePath string
 // @Accept json
 // @Produce json
 // @Param DepartmentInfo body models.Department true "Create department"
+// @Param Idempotency-Key header string false "Unique key of the request, retries with the same key are replayed"
 // @Success 201 {object} models.DepartmentResp
-// @Failure 400 string Error models.ResponseError
-// @Failure 500 string Error models.ResponseError
+// @Failure 400 {object} models.ResponseError
+// @Failure 409 {object} models.ResponseError
+// @Failure 422 {object} models.ResponseError
+// @Failure 500 {object} models.ResponseError
 func (h *handlerV1) CreateDepartment(c *gin.Context) {
 	var (
 		body       models.Department
@@ -38,12 +41,12 @@ func (h *handlerV1) CreateDepartment(c *gin.Context) {
 
 	jspMarshal.UseProtoNames = true
 	err := c.ShouldBindJSON(&body)
-	if handleBadRequestErrWithMessage(c, h.log, err, ErrorCodeInvalidJSON) {
+	if handleBindErr(c, h.log, err) {
 		return
 	}
 
 	err = body.Validate()
-	if handleBadRequestErrWithMessage(c, h.log, err, ErrorValidationError) {
+	if handleValidationErr(c, h.log, err) {
 		return
 	}
 
@@ -55,24 +58,16 @@ func (h *handlerV1) CreateDepartment(c *gin.Context) {
 		ImageUrl:    body.ImageUrl,
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second*time.Duration(h.cfg.CtxTimeout))
+	ctx, cancel := context.WithTimeout(c.Request.Context(), time.Second*time.Duration(h.cfg.CtxTimeout))
 	defer cancel()
 
 	respDepartment, err := h.serviceManager.HealthCareService().CreateDepartment(ctx, createReq)
-	if handleInternalServerErrorWithMessage(c, h.log, err, "error while creating a department") {
+	if handleGrpcErrWithMessage(c, h.log, err, "error while creating a department") {
 		return
 	}
 
-	response := models.DepartmentResp{
-		ID:          respDepartment.Id,
-		Name:        respDepartment.Name,
-		Description: respDepartment.Description,
-		ComeTime:    respDepartment.ComeTime,
-		FinishTime:  respDepartment.FinishTime,
-		ImageUrl:    respDepartment.ImageUrl,
-		CreatedAt:   respDepartment.CreatedAt,
-		UpdatedAt:   respDepartment.UpdatedAt,
-	}
+	response := viewerOf(c).department(respDepartment)
+	auditChange(c, response.ID, response)
 
 	c.JSON(http.StatusOK, response)
 }
@@ -86,9 +81,11 @@ func (h *handlerV1) CreateDepartment(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param id path int64 true "id"
+// @Param If-None-Match header string false "ETag of the version the client has"
 // @Success 201 {object} models.DepartmentResp
-// @Failure 400 string Error models.ResponseError
-// @Failure 500 string Error models.ResponseError
+// @Failure 400 {object} models.ResponseError
+// @Failure 404 {object} models.ResponseError
+// @Failure 500 {object} models.ResponseError
 func (h *handlerV1) GetDepartmentById(c *gin.Context) {
 	var jspMarshal protojson.MarshalOptions
 	jspMarshal.UseProtoNames = true
@@ -99,26 +96,17 @@ func (h *handlerV1) GetDepartmentById(c *gin.Context) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second*time.Duration((h.cfg.CtxTimeout)))
+	ctx, cancel := context.WithTimeout(c.Request.Context(), time.Second*time.Duration((h.cfg.CtxTimeout)))
 	defer cancel()
 
 	respDepartment, err := h.serviceManager.HealthCareService().GetDepartmentById(ctx, &pb.GetReqInt{Id: int64(idToInt)})
-	if handleInternalServerErrorWithMessage(c, h.log, err, "error while getting department by id") {
+	if handleGrpcErrWithMessage(c, h.log, err, "error while getting department by id") {
 		return
 	}
 
-	response := models.DepartmentResp{
-		ID:          respDepartment.Id,
-		Name:        respDepartment.Name,
-		Description: respDepartment.Description,
-		ComeTime:    respDepartment.ComeTime,
-		FinishTime:  respDepartment.FinishTime,
-		ImageUrl:    respDepartment.ImageUrl,
-		CreatedAt:   respDepartment.CreatedAt,
-		UpdatedAt:   respDepartment.UpdatedAt,
-	}
+	response := viewerOf(c).department(respDepartment)
 
-	c.JSON(http.StatusOK, response)
+	writeWithETag(c, response)
 }
 
 // Update Department
@@ -131,9 +119,13 @@ func (h *handlerV1) GetDepartmentById(c *gin.Context) {
 // @Produce json
 // @Param id path int64 false "id"
 // @Param UserInfo body models.DoctorUpdateReq true "Update Department"
+// @Param If-Match header string false "ETag of the version being updated"
 // @Success 201 {object} models.DoctorResp
-// @Failure 400 string Error models.ResponseError
-// @Failure 500 string Error models.ResponseError
+// @Failure 400 {object} models.ResponseError
+// @Failure 404 {object} models.ResponseError
+// @Failure 412 {object} models.ResponseError
+// @Failure 428 {object} models.ResponseError
+// @Failure 500 {object} models.ResponseError
 func (h *handlerV1) UpdateDepartment(c *gin.Context) {
 	var (
 		body        models.Department
@@ -142,7 +134,7 @@ func (h *handlerV1) UpdateDepartment(c *gin.Context) {
 
 	jspbMarshal.UseProtoNames = true
 	err := c.ShouldBindJSON(&body)
-	if handleBadRequestErrWithMessage(c, h.log, err, ErrorCodeInvalidJSON) {
+	if handleBindErr(c, h.log, err) {
 		return
 	}
 
@@ -167,27 +159,30 @@ func (h *handlerV1) UpdateDepartment(c *gin.Context) {
 			return
 		}
 		body.ID = int32(idToInt)
+		updateReq.Id = body.ID
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second*time.Duration(h.cfg.CtxTimeout))
+	ctx, cancel := context.WithTimeout(c.Request.Context(), time.Second*time.Duration(h.cfg.CtxTimeout))
 	defer cancel()
 
-	respDepartment, err := h.serviceManager.HealthCareService().UpdateDepartment(ctx, updateReq)
-	if handleInternalServerErrorWithMessage(c, h.log, err, ErrorCodeInternalServerError) {
+	if !h.checkIfMatch(c, func() (interface{}, error) {
+		department, err := h.serviceManager.HealthCareService().GetDepartmentById(ctx, &pb.GetReqInt{Id: int64(updateReq.Id)})
+		if err != nil {
+			return nil, err
+		}
+		return viewerOf(c).department(department), nil
+	}) {
 		return
 	}
 
-	response := models.DepartmentResp{
-		ID:          respDepartment.Id,
-		Name:        respDepartment.Name,
-		Description: respDepartment.Description,
-		ComeTime:    respDepartment.ComeTime,
-		FinishTime:  respDepartment.FinishTime,
-		ImageUrl:    respDepartment.ImageUrl,
-		CreatedAt:   respDepartment.CreatedAt,
-		UpdatedAt:   respDepartment.UpdatedAt,
+	respDepartment, err := h.serviceManager.HealthCareService().UpdateDepartment(ctx, updateReq)
+	if handleGrpcErrWithMessage(c, h.log, err, "error while updating department") {
+		return
 	}
 
+	response := viewerOf(c).department(respDepartment)
+	auditChange(c, response.ID, response)
+
 	c.JSON(http.StatusOK, response)
 }
 
@@ -201,23 +196,24 @@ func (h *handlerV1) UpdateDepartment(c *gin.Context) {
 // @Produce json
 // @Param id path int64 true "id"
 // @Success 201 {object} models.Status
-// @Failure 400 string Error models.ResponseError
-// @Failure 500 string Error models.ResponseError
+// @Failure 400 {object} models.ResponseError
+// @Failure 404 {object} models.ResponseError
+// @Failure 500 {object} models.ResponseError
 func (h *handlerV1) DeleteDepartment(c *gin.Context) {
 	var jspbMarshal protojson.MarshalOptions
 	jspbMarshal.UseProtoNames = true
 
 	id := c.Param("id")
 
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second*time.Duration(h.cfg.CtxTimeout))
+	ctx, cancel := context.WithTimeout(c.Request.Context(), time.Second*time.Duration(h.cfg.CtxTimeout))
 	defer cancel()
 
 	_, err := h.serviceManager.HealthCareService().DeleteDoctor(ctx, &pb.GetReqStr{Id: id})
-	if handleInternalServerErrorWithMessage(c, h.log, err, "error while deleting department") {
+	if handleGrpcErrWithMessage(c, h.log, err, "error while deleting department") {
 		return
 	}
 
-	c.JSON(http.StatusOK, models.Status{Message: "department was successfully deleted"})
+	c.JSON(http.StatusOK, models.Status{Message: translate(c, "department was successfully deleted")})
 }
 
 // List departments
@@ -231,8 +227,8 @@ func (h *handlerV1) DeleteDepartment(c *gin.Context) {
 // @Param page path string false "page"
 // @Param limit path string false "limit"
 // @Success 201 {object} models.ListDepartments
-// @Failure 400 string Error models.ResponseError
-// @Failure 500 string Error models.ResponseError
+// @Failure 400 {object} models.ResponseError
+// @Failure 500 {object} models.ResponseError
 func (h *handlerV1) ListDepartments(c *gin.Context) {
 	var jspbMarshal protojson.MarshalOptions
 	jspbMarshal.UseProtoNames = true
@@ -246,11 +242,11 @@ func (h *handlerV1) ListDepartments(c *gin.Context) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second*time.Duration(h.cfg.CtxTimeout))
+	ctx, cancel := context.WithTimeout(c.Request.Context(), time.Second*time.Duration(h.cfg.CtxTimeout))
 	defer cancel()
 
 	response, err := h.serviceManager.HealthCareService().GetAllDepartments(ctx, &pb.GetAll{Page: int64(page), Limit: int64(limit)})
-	if handleInternalServerErrorWithMessage(c, h.log, err, ErrorCodeInternalServerError) {
+	if handleGrpcErrWithMessage(c, h.log, err, "error while listing departments") {
 		return
 	}
 
@@ -266,12 +262,11 @@ func (h *handlerV1) ListDepartments(c *gin.Context) {
 // @Produce json
 // @Param file formData file true "file"
 // @Success 201 {object} models.URL
-// @Failure 400 string Error models.ResponseError
-// @Failure 500 string Error models.ResponseError
+// @Failure 400 {object} models.ResponseError
+// @Failure 500 {object} models.ResponseError
 func (h *handlerV1) UploadDepartmentFile(c *gin.Context) {
 	file, header, err := c.Request.FormFile("file")
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Bad request"})
+	if handleBadRequestErrWithMessage(c, h.log, err, ErrorBadRequest) {
 		return
 	}
 	defer file.Close()
@@ -282,15 +277,13 @@ func (h *handlerV1) UploadDepartmentFile(c *gin.Context) {
 	uploadPath := filepath.Join(dst, "media", "departments")
 
 	err = os.MkdirAll(uploadPath, os.ModePerm)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create upload directory"})
+	if handleInternalServerErrorWithMessage(c, h.log, err, "failed to create upload directory") {
 		return
 	}
 
 	FilePath = filepath.Join(uploadPath, fileName)
 	err = c.SaveUploadedFile(header, FilePath)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save file"})
+	if handleInternalServerErrorWithMessage(c, h.log, err, "failed to save file") {
 		return
 	}
 
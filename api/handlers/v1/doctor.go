@@ -8,6 +8,9 @@ import (
 	"myproject/admin-api-gateway/api/models"
 	"myproject/admin-api-gateway/email"
 	"myproject/admin-api-gateway/pkg/etc"
+	"myproject/admin-api-gateway/pkg/featureflag"
+	"myproject/admin-api-gateway/pkg/i18n"
+	"myproject/admin-api-gateway/pkg/logger"
 	"net/http"
 	"os"
 	"path/filepath"
@@ -32,9 +35,14 @@ import (
 // @Produce json
 // @Param DoctorInfo body models.DoctorReq true "Register doctor"
 // @Success 201 {object} models.RegisterRespModel
-// @Failure 400 string Error models.ResponseError
-// @Failure 500 string Error models.ResponseError
+// @Failure 400 {object} models.ResponseError
+// @Failure 403 {object} models.ResponseError
+// @Failure 500 {object} models.ResponseError
 func (h *handlerV1) RegisterDoctor(c *gin.Context) {
+	if h.featureDisabled(c, featureflag.DoctorRegistrationClosed, "Doctor registration is closed") {
+		return
+	}
+
 	var (
 		body       models.DoctorReq
 		code       string
@@ -43,8 +51,8 @@ func (h *handlerV1) RegisterDoctor(c *gin.Context) {
 
 	jspMarshal.UseProtoNames = true
 
-	err := c.BindJSON(&body)
-	if handleBadRequestErrWithMessage(c, h.log, err, ErrorCodeInvalidJSON) {
+	err := c.ShouldBindJSON(&body)
+	if handleBindErr(c, h.log, err) {
 		return
 	}
 
@@ -53,11 +61,11 @@ func (h *handlerV1) RegisterDoctor(c *gin.Context) {
 	body.Email = strings.ToLower(body.Email)
 
 	err = body.Validate()
-	if handleBadRequestErrWithMessage(c, h.log, err, ErrorValidationError) {
+	if handleValidationErr(c, h.log, err) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second*time.Duration(h.cfg.CtxTimeout))
+	ctx, cancel := context.WithTimeout(c.Request.Context(), time.Second*time.Duration(h.cfg.CtxTimeout))
 	defer cancel()
 
 	exists, err := h.serviceManager.HealthCareService().CheckUniques(ctx, &pb.CheckUniqReq{
@@ -65,7 +73,7 @@ func (h *handlerV1) RegisterDoctor(c *gin.Context) {
 		Value: body.Email,
 	})
 
-	if handleInternalServerErrorWithMessage(c, h.log, err, "failed to check email uniqueness") {
+	if handleGrpcErrWithMessage(c, h.log, err, "failed to check email uniqueness") {
 		return
 	}
 
@@ -102,24 +110,26 @@ func (h *handlerV1) RegisterDoctor(c *gin.Context) {
 
 	timeOut := time.Second * 300
 
-	err = h.inMemoryStorage.SetWithTTL(registerDoctor.Email, string(doctorJson), int(timeOut.Seconds()))
+	err = h.inMemoryStorage.SetWithTTL(ctx, registerDoctor.Email, string(doctorJson), int(timeOut.Seconds()))
 	if handleInternalServerErrorWithMessage(c, h.log, err, "error while setting with ttl to redis") {
 		return
 	}
 
+	lang := i18n.FromContext(c.Request.Context())
 	message, err := email.SendVerificationCode(email.EmailPayload{
 		From:     h.cfg.SendEmailFrom,
 		To:       registerDoctor.Email,
 		Password: h.cfg.EmailCode,
 		Code:     registerDoctor.Code,
-		Message:  fmt.Sprintf("Hi, %s", registerDoctor.FullName),
+		Message:  i18n.Tf(lang, "Hi, %s", registerDoctor.FullName),
+		Language: lang,
 	})
 	if handleInternalServerErrorWithMessage(c, h.log, err, "error while sending code to doctor's email") {
 		return
 	}
 
 	c.JSON(http.StatusOK, models.RegisterRespModel{
-		Message: message,
+		Message: translate(c, message),
 	})
 }
 
@@ -133,8 +143,7 @@ func (h *handlerV1) RegisterDoctor(c *gin.Context) {
 // @Param email path string true "email"
 // @Param code path string true "code"
 // @Success 201 {object} models.Status
-// @Failure 400 string error models.ResponseError
-// @Failure 400 string error models.ResponseError
+// @Failure 400 {object} models.ResponseError
 func (h *handlerV1) VerifyDoctor(c *gin.Context) {
 	var jspMarshal protojson.MarshalOptions
 	jspMarshal.UseProtoNames = true
@@ -142,13 +151,10 @@ func (h *handlerV1) VerifyDoctor(c *gin.Context) {
 	doctorEmail := c.Param("email")
 	doctorCode := c.Param("code")
 
-	registeredDoctor, err := redis.Bytes(h.inMemoryStorage.Get(doctorEmail))
+	registeredDoctor, err := redis.Bytes(h.inMemoryStorage.Get(c.Request.Context(), doctorEmail))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.StandardErrorModel{
-			Status:  ErrorCodeNotFound,
-			Message: "Code is expired, try again",
-		})
-		h.log.Error("Code is expired, TTL is over.")
+		AbortWithError(c, http.StatusBadRequest, ErrorCodeNotFound, "Code is expired, try again")
+		logger.WithContext(h.log, c.Request.Context()).Error("Code is expired, TTL is over.")
 		return
 	}
 
@@ -175,7 +181,7 @@ func (h *handlerV1) VerifyDoctor(c *gin.Context) {
 		Role:      "doctor",
 		SignInKey: h.cfg.SignInKey,
 		Log:       h.log,
-		TimeOut:   h.cfg.AccessTokenTimeOut,
+		TimeOut:   h.cfg.AccessTokenTimeout,
 	}
 
 	_, refresh, err := h.jwtHandler.GenerateAuthJWT()
@@ -183,7 +189,7 @@ func (h *handlerV1) VerifyDoctor(c *gin.Context) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second*time.Duration(h.cfg.CtxTimeout))
+	ctx, cancel := context.WithTimeout(c.Request.Context(), time.Second*time.Duration(h.cfg.CtxTimeout))
 	defer cancel()
 
 	_, err = h.serviceManager.HealthCareService().CreateDoctor(ctx, &pb.Doctor{
@@ -204,11 +210,11 @@ func (h *handlerV1) VerifyDoctor(c *gin.Context) {
 		RefreshToken:  refresh,
 		IsVerified:    false,
 	})
-	if handleInternalServerErrorWithMessage(c, h.log, err, "error while creating doctor(is_verified = false yet)") {
+	if handleGrpcErrWithMessage(c, h.log, err, "error while creating doctor(is_verified = false yet)") {
 		return
 	}
 	c.JSON(http.StatusOK, models.Status{
-		Message: "registration completed successfully, wait for the admin's verification",
+		Message: translate(c, "registration completed successfully, wait for the admin's verification"),
 	})
 }
 
@@ -220,8 +226,7 @@ func (h *handlerV1) VerifyDoctor(c *gin.Context) {
 // @Produce json
 // @Param User body models.LoginReqModel true "Login"
 // @Success 201 {object} models.LoginRespDoctor
-// @Failure 400 string Error models.ResponseError
-// @Failure 400 string Error models.ResponseError
+// @Failure 400 {object} models.ResponseError
 // @Router /v1/doctor/login [post]
 func (h *handlerV1) LoginDoctor(c *gin.Context) {
 	var (
@@ -230,17 +235,17 @@ func (h *handlerV1) LoginDoctor(c *gin.Context) {
 	)
 
 	jspMarshal.UseProtoNames = true
-	err := c.ShouldBind(&body)
-	if handleBadRequestErrWithMessage(c, h.log, err, "error while marshaling doctor request body") {
+	err := c.ShouldBindJSON(&body)
+	if handleBindErr(c, h.log, err) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second*time.Duration(h.cfg.CtxTimeout))
+	ctx, cancel := context.WithTimeout(c.Request.Context(), time.Second*time.Duration(h.cfg.CtxTimeout))
 	defer cancel()
 
 	doctor, err := h.serviceManager.HealthCareService().Exists(ctx, &pb.Email{Email: body.Email})
 
-	if handleInternalServerErrorWithMessage(c, h.log, err, "error while checking if doctor exists") {
+	if handleGrpcErrWithMessage(c, h.log, err, "error while checking if doctor exists") {
 		return
 	}
 
@@ -251,11 +256,11 @@ func (h *handlerV1) LoginDoctor(c *gin.Context) {
 	}
 
 	h.jwtHandler = tokens.JWTHandler{
-		Sub:       doctor.Password,
+		Sub:       doctor.Id,
 		Role:      "doctor",
 		SignInKey: h.cfg.SignInKey,
 		Log:       h.log,
-		TimeOut:   h.cfg.AccessTokenTimeOut,
+		TimeOut:   h.cfg.AccessTokenTimeout,
 	}
 
 	access, _, err := h.jwtHandler.GenerateAuthJWT()
@@ -264,24 +269,8 @@ func (h *handlerV1) LoginDoctor(c *gin.Context) {
 	}
 
 	loginResp := models.LoginRespDoctor{
-		ID:            doctor.Id,
-		FullName:      doctor.FullName,
-		Password:      doctor.Password,
-		BirthDate:     doctor.BirthDate,
-		Gender:        doctor.Gender,
-		PhoneNumber:   doctor.PhoneNumber,
-		Email:         doctor.Email,
-		Address:       doctor.Address,
-		Salary:        float64(doctor.Salary),
-		Biography:     doctor.Biography,
-		StartWorkYear: doctor.StartWorkYear,
-		EndWorkYear:   doctor.EndWorkYear,
-		WorkYears:     doctor.WorkYears,
-		DepartmentId:  doctor.DepartmentId,
-		AccessToken:   access,
-		IsVerified:    doctor.IsVerified,
-		CreatedAt:     doctor.CreatedAt,
-		UpdatedAt:     doctor.UpdatedAt,
+		DoctorResp:  *viewerOf(c).doctor(doctor),
+		AccessToken: access,
 	}
 
 	c.JSON(http.StatusOK, loginResp)
@@ -296,9 +285,12 @@ func (h *handlerV1) LoginDoctor(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param DoctorInfo body models.DoctorReq true "Create doctor"
+// @Param Idempotency-Key header string false "Unique key of the request, retries with the same key are replayed"
 // @Success 201 {object} models.DoctorModel
-// @Failure 400 string Error models.ResponseError
-// @Failure 500 string Error models.ResponseError
+// @Failure 400 {object} models.ResponseError
+// @Failure 409 {object} models.ResponseError
+// @Failure 422 {object} models.ResponseError
+// @Failure 500 {object} models.ResponseError
 func (h *handlerV1) CreateDoctor(c *gin.Context) {
 	var (
 		body       models.DoctorReq
@@ -307,7 +299,7 @@ func (h *handlerV1) CreateDoctor(c *gin.Context) {
 
 	jspMarshal.UseProtoNames = true
 	err := c.ShouldBindJSON(&body)
-	if handleBadRequestErrWithMessage(c, h.log, err, ErrorCodeInvalidJSON) {
+	if handleBindErr(c, h.log, err) {
 		return
 	}
 
@@ -320,11 +312,11 @@ func (h *handlerV1) CreateDoctor(c *gin.Context) {
 	body.Email = strings.TrimSpace(body.Email)
 	body.Email = strings.ToLower(body.Email)
 	err = body.Validate()
-	if handleBadRequestErrWithMessage(c, h.log, err, ErrorValidationError) {
+	if handleValidationErr(c, h.log, err) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second*time.Duration(h.cfg.CtxTimeout))
+	ctx, cancel := context.WithTimeout(c.Request.Context(), time.Second*time.Duration(h.cfg.CtxTimeout))
 	defer cancel()
 
 	body.ID = uuid.New().String()
@@ -333,7 +325,7 @@ func (h *handlerV1) CreateDoctor(c *gin.Context) {
 		Role:      "doctor",
 		SignInKey: h.cfg.SignInKey,
 		Log:       h.log,
-		TimeOut:   h.cfg.AccessTokenTimeOut,
+		TimeOut:   h.cfg.AccessTokenTimeout,
 	}
 	access, refresh, err := h.jwtHandler.GenerateAuthJWT()
 	if handleInternalServerErrorWithMessage(c, h.log, err, "error while generating access and refresh token") {
@@ -360,30 +352,15 @@ func (h *handlerV1) CreateDoctor(c *gin.Context) {
 	}
 
 	respDoctor, err := h.serviceManager.HealthCareService().CreateDoctor(ctx, createReq)
-	if handleInternalServerErrorWithMessage(c, h.log, err, "error while creating a doctor") {
+	if handleGrpcErrWithMessage(c, h.log, err, "error while creating a doctor") {
 		return
 	}
 
 	response := models.DoctorModel{
-		ID:            respDoctor.Id,
-		FullName:      respDoctor.FullName,
-		BirthDate:     respDoctor.BirthDate,
-		Gender:        respDoctor.Gender,
-		PhoneNumber:   respDoctor.PhoneNumber,
-		Email:         respDoctor.Email,
-		Address:       respDoctor.Address,
-		Salary:        float64(respDoctor.Salary),
-		Biography:     respDoctor.Biography,
-		StartWorkYear: respDoctor.StartWorkYear,
-		EndWorkYear:   respDoctor.EndWorkYear,
-		WorkYears:     respDoctor.WorkYears,
-		DepartmentId:  respDoctor.DepartmentId,
-		SpecIds:       respDoctor.SpecIds,
-		IsVerified:    respDoctor.IsVerified,
-		AccessToken:   access,
-		CreatedAt:     respDoctor.CreatedAt,
-		UpdatedAt:     respDoctor.UpdatedAt,
+		DoctorResp:  *viewerOf(c).doctor(respDoctor),
+		AccessToken: access,
 	}
+	auditChange(c, response.ID, response)
 
 	c.JSON(http.StatusCreated, response)
 }
@@ -397,45 +374,28 @@ func (h *handlerV1) CreateDoctor(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param id path string true "id"
+// @Param If-None-Match header string false "ETag of the version the client has"
 // @Success 201 {object} models.DoctorResp
-// @Failure 400 string Error models.ResponseError
-// @Failure 500 string Error models.ResponseError
+// @Failure 400 {object} models.ResponseError
+// @Failure 404 {object} models.ResponseError
+// @Failure 500 {object} models.ResponseError
 func (h *handlerV1) GetDoctorById(c *gin.Context) {
 	var jspMarshal protojson.MarshalOptions
 	jspMarshal.UseProtoNames = true
 
 	id := c.Param("id")
 
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second*time.Duration((h.cfg.CtxTimeout)))
+	ctx, cancel := context.WithTimeout(c.Request.Context(), time.Second*time.Duration((h.cfg.CtxTimeout)))
 	defer cancel()
 
 	respDoctor, err := h.serviceManager.HealthCareService().GetDoctorById(ctx, &pb.GetReqStr{Id: id})
-	if handleInternalServerErrorWithMessage(c, h.log, err, "error while getting doctor by id") {
-		return
-	}
-
-	response := models.DoctorResp{
-		ID:            respDoctor.Id,
-		FullName:      respDoctor.FullName,
-		BirthDate:     respDoctor.BirthDate,
-		Gender:        respDoctor.Gender,
-		PhoneNumber:   respDoctor.PhoneNumber,
-		Email:         respDoctor.Email,
-		Password:      respDoctor.Password,
-		Address:       respDoctor.Address,
-		Salary:        float64(respDoctor.Salary),
-		Biography:     respDoctor.Biography,
-		StartWorkYear: respDoctor.StartWorkYear,
-		EndWorkYear:   respDoctor.EndWorkYear,
-		WorkYears:     respDoctor.WorkYears,
-		DepartmentId:  respDoctor.DepartmentId,
-		SpecIds:       respDoctor.SpecIds,
-		IsVerified:    respDoctor.IsVerified,
-		CreatedAt:     respDoctor.CreatedAt,
-		UpdatedAt:     respDoctor.UpdatedAt,
+	if handleGrpcErrWithMessage(c, h.log, err, "error while getting doctor by id") {
+		return
 	}
 
-	c.JSON(http.StatusOK, response)
+	response := viewerOf(c).doctor(respDoctor)
+
+	writeWithETag(c, response)
 }
 
 // Update doctor
@@ -448,9 +408,13 @@ func (h *handlerV1) GetDoctorById(c *gin.Context) {
 // @Produce json
 // @Param id path string false "id"
 // @Param UserInfo body models.DoctorUpdateReq true "Update Doctor"
+// @Param If-Match header string false "ETag of the version being updated"
 // @Success 201 {object} models.DoctorResp
-// @Failure 400 string Error models.ResponseError
-// @Failure 500 string Error models.ResponseError
+// @Failure 400 {object} models.ResponseError
+// @Failure 404 {object} models.ResponseError
+// @Failure 412 {object} models.ResponseError
+// @Failure 428 {object} models.ResponseError
+// @Failure 500 {object} models.ResponseError
 func (h *handlerV1) UpdateDoctor(c *gin.Context) {
 	var (
 		body        models.DoctorUpdateReq
@@ -459,11 +423,11 @@ func (h *handlerV1) UpdateDoctor(c *gin.Context) {
 
 	jspbMarshal.UseProtoNames = true
 	err := c.ShouldBindJSON(&body)
-	if handleBadRequestErrWithMessage(c, h.log, err, ErrorCodeInvalidJSON) {
+	if handleBindErr(c, h.log, err) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second*time.Duration(h.cfg.CtxTimeout))
+	ctx, cancel := context.WithTimeout(c.Request.Context(), time.Second*time.Duration(h.cfg.CtxTimeout))
 	defer cancel()
 
 	updateReq := &pb.Doctor{
@@ -489,32 +453,24 @@ func (h *handlerV1) UpdateDoctor(c *gin.Context) {
 		updateReq.Id = id
 	}
 
+	if !h.checkIfMatch(c, func() (interface{}, error) {
+		doctor, err := h.serviceManager.HealthCareService().GetDoctorById(ctx, &pb.GetReqStr{Id: updateReq.Id})
+		if err != nil {
+			return nil, err
+		}
+		return viewerOf(c).doctor(doctor), nil
+	}) {
+		return
+	}
+
 	respDoctor, err := h.serviceManager.HealthCareService().UpdateDoctor(ctx, updateReq)
-	if handleInternalServerErrorWithMessage(c, h.log, err, ErrorCodeInternalServerError) {
-		return
-	}
-
-	response := models.DoctorResp{
-		ID:            respDoctor.Id,
-		FullName:      respDoctor.FullName,
-		BirthDate:     respDoctor.BirthDate,
-		Gender:        respDoctor.Gender,
-		PhoneNumber:   respDoctor.PhoneNumber,
-		Email:         respDoctor.Email,
-		Password:      respDoctor.Password,
-		Address:       respDoctor.Address,
-		Salary:        float64(respDoctor.Salary),
-		Biography:     respDoctor.Biography,
-		StartWorkYear: respDoctor.StartWorkYear,
-		EndWorkYear:   respDoctor.EndWorkYear,
-		WorkYears:     respDoctor.WorkYears,
-		DepartmentId:  respDoctor.DepartmentId,
-		SpecIds:       respDoctor.SpecIds,
-		IsVerified:    respDoctor.IsVerified,
-		CreatedAt:     respDoctor.CreatedAt,
-		UpdatedAt:     respDoctor.UpdatedAt,
+	if handleGrpcErrWithMessage(c, h.log, err, "error while updating doctor") {
+		return
 	}
 
+	response := viewerOf(c).doctor(respDoctor)
+	auditChange(c, response.ID, response)
+
 	c.JSON(http.StatusOK, response)
 }
 
@@ -528,23 +484,24 @@ func (h *handlerV1) UpdateDoctor(c *gin.Context) {
 // @Produce json
 // @Param id path string true "id"
 // @Success 201 {object} models.Status
-// @Failure 400 string Error models.ResponseError
-// @Failure 500 string Error models.ResponseError
+// @Failure 400 {object} models.ResponseError
+// @Failure 404 {object} models.ResponseError
+// @Failure 500 {object} models.ResponseError
 func (h *handlerV1) DeleteDoctor(c *gin.Context) {
 	var jspbMarshal protojson.MarshalOptions
 	jspbMarshal.UseProtoNames = true
 
 	id := c.Param("id")
 
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second*time.Duration(h.cfg.CtxTimeout))
+	ctx, cancel := context.WithTimeout(c.Request.Context(), time.Second*time.Duration(h.cfg.CtxTimeout))
 	defer cancel()
 
 	_, err := h.serviceManager.HealthCareService().DeleteDoctor(ctx, &pb.GetReqStr{Id: id})
-	if handleInternalServerErrorWithMessage(c, h.log, err, "error while deleting doctor") {
+	if handleGrpcErrWithMessage(c, h.log, err, "error while deleting doctor") {
 		return
 	}
 
-	c.JSON(http.StatusOK, models.Status{Message: "doctor was successfully deleted"})
+	c.JSON(http.StatusOK, models.Status{Message: translate(c, "doctor was successfully deleted")})
 }
 
 // List doctors
@@ -558,8 +515,8 @@ func (h *handlerV1) DeleteDoctor(c *gin.Context) {
 // @Param page path string false "page"
 // @Param limit path string false "limit"
 // @Success 201 {object} models.ListDoctors
-// @Failure 400 string Error models.ResponseError
-// @Failure 500 string Error models.ResponseError
+// @Failure 400 {object} models.ResponseError
+// @Failure 500 {object} models.ResponseError
 func (h *handlerV1) ListDoctors(c *gin.Context) {
 	var jspbMarshal protojson.MarshalOptions
 	jspbMarshal.UseProtoNames = true
@@ -573,15 +530,15 @@ func (h *handlerV1) ListDoctors(c *gin.Context) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second*time.Duration(h.cfg.CtxTimeout))
+	ctx, cancel := context.WithTimeout(c.Request.Context(), time.Second*time.Duration(h.cfg.CtxTimeout))
 	defer cancel()
 
-	response, err := h.serviceManager.HealthCareService().GetAllDoctors(ctx, &pb.GetAll{Page: int64(page), Limit: int64(limit)})
-	if handleInternalServerErrorWithMessage(c, h.log, err, ErrorCodeInternalServerError) {
+	resp, err := h.serviceManager.HealthCareService().GetAllDoctors(ctx, &pb.GetAll{Page: int64(page), Limit: int64(limit)})
+	if handleGrpcErrWithMessage(c, h.log, err, "error while listing doctors") {
 		return
 	}
 
-	c.JSON(http.StatusOK, response)
+	c.JSON(http.StatusOK, models.ListDoctors{Count: resp.Count, Doctors: viewerOf(c).doctors(resp.Doctors)})
 }
 
 // List doctors by department id
@@ -596,8 +553,8 @@ func (h *handlerV1) ListDoctors(c *gin.Context) {
 // @Param limit path string false "limit"
 // @Param department_id path int64 true "department_id"
 // @Success 201 {object} models.ListDoctors
-// @Failure 400 string Error models.ResponseError
-// @Failure 500 string Error models.ResponseError
+// @Failure 400 {object} models.ResponseError
+// @Failure 500 {object} models.ResponseError
 func (h *handlerV1) ListDoctorsByDepartmentId(c *gin.Context) {
 	var jspbMarshal protojson.MarshalOptions
 	jspbMarshal.UseProtoNames = true
@@ -618,14 +575,14 @@ func (h *handlerV1) ListDoctorsByDepartmentId(c *gin.Context) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second*time.Duration(h.cfg.CtxTimeout))
+	ctx, cancel := context.WithTimeout(c.Request.Context(), time.Second*time.Duration(h.cfg.CtxTimeout))
 	defer cancel()
-	response, err := h.serviceManager.HealthCareService().GetAllDoctorsByDepartmentId(ctx, &pb.GetRequest{Page: int64(page), Limit: int64(limit), Id: int64(idToInt)})
-	if handleInternalServerErrorWithMessage(c, h.log, err, ErrorCodeInternalServerError) {
+	resp, err := h.serviceManager.HealthCareService().GetAllDoctorsByDepartmentId(ctx, &pb.GetRequest{Page: int64(page), Limit: int64(limit), Id: int64(idToInt)})
+	if handleGrpcErrWithMessage(c, h.log, err, "error while listing doctors of a department") {
 		return
 	}
 
-	c.JSON(http.StatusOK, response)
+	c.JSON(http.StatusOK, models.ListDoctors{Count: resp.Count, Doctors: viewerOf(c).doctors(resp.Doctors)})
 }
 
 // Upload files
@@ -637,12 +594,11 @@ func (h *handlerV1) ListDoctorsByDepartmentId(c *gin.Context) {
 // @Produce json
 // @Param file formData file true "file"
 // @Success 201 {object} models.URL
-// @Failure 400 string Error models.ResponseError
-// @Failure 500 string Error models.ResponseError
+// @Failure 400 {object} models.ResponseError
+// @Failure 500 {object} models.ResponseError
 func (h *handlerV1) UploadFile(c *gin.Context) {
 	file, header, err := c.Request.FormFile("file")
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Bad request"})
+	if handleBadRequestErrWithMessage(c, h.log, err, ErrorBadRequest) {
 		return
 	}
 	defer file.Close()
@@ -653,15 +609,13 @@ func (h *handlerV1) UploadFile(c *gin.Context) {
 	uploadPath := filepath.Join(dst, "media", "doctors")
 
 	err = os.MkdirAll(uploadPath, os.ModePerm)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create upload directory"})
+	if handleInternalServerErrorWithMessage(c, h.log, err, "failed to create upload directory") {
 		return
 	}
 
 	filePath := filepath.Join(uploadPath, fileName)
 	err = c.SaveUploadedFile(header, filePath)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save file"})
+	if handleInternalServerErrorWithMessage(c, h.log, err, "failed to save file") {
 		return
 	}
 
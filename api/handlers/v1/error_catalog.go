@@ -0,0 +1,34 @@
+// Code generated by scripts/errcatalog from handler.go; DO NOT EDIT.
+
+package v1
+
+import "myproject/admin-api-gateway/api/models"
+
+// ErrorCatalog lists every error code the API responds with
+var ErrorCatalog = []models.ErrorCatalogEntry{
+	{Code: ErrorCodeInvalidURL, HTTPStatus: 400, Description: "A path parameter is malformed"},
+	{Code: ErrorCodeInvalidJSON, HTTPStatus: 400, Description: "The request body is not valid JSON, \"fields\" names fields of the wrong type"},
+	{Code: ErrorCodeInvalidParams, HTTPStatus: 400, Description: "A query or path parameter is missing or invalid"},
+	{Code: ErrorCodeInternalServerError, HTTPStatus: 500, Description: "Unexpected failure, the request id identifies it in the logs"},
+	{Code: ErrorCodeUnauthorized, HTTPStatus: 401, Description: "The access token is missing, invalid or expired"},
+	{Code: ErrorCodeAlreadyExists, HTTPStatus: 409, Description: "A resource with the same unique fields exists"},
+	{Code: ErrorCodeNotFound, HTTPStatus: 404, Description: "The resource or route does not exist"},
+	{Code: ErrorCodeInvalidCode, HTTPStatus: 400, Description: "The verification code is wrong"},
+	{Code: ErrorBadRequest, HTTPStatus: 400, Description: "The request cannot be processed as sent"},
+	{Code: ErrorInvalidCredentials, HTTPStatus: 400, Description: "The username or password is wrong"},
+	{Code: StatusMethodNotAllowed, HTTPStatus: 405, Description: "The role of the caller may not use this route"},
+	{Code: ErrorValidationError, HTTPStatus: 400, Description: "The request body failed validation, \"fields\" names the rejected fields"},
+	{Code: ErrorCodeTooManyRequests, HTTPStatus: 429, Description: "Rate limit exceeded, see the Retry-After header"},
+	{Code: ErrorCodeForbidden, HTTPStatus: 403, Description: "The caller may not access this resource"},
+	{Code: ErrorCodeInvalidArgument, HTTPStatus: 400, Description: "A backend service rejected an argument"},
+	{Code: ErrorCodeServiceUnavailable, HTTPStatus: 503, Description: "A backend service is unavailable, retry later"},
+	{Code: ErrorCodeGatewayTimeout, HTTPStatus: 504, Description: "A backend service did not answer in time"},
+	{Code: ErrorCodePreconditionFailed, HTTPStatus: 412, Description: "The If-Match ETag does not match the current version"},
+	{Code: ErrorCodePreconditionNeeded, HTTPStatus: 428, Description: "Updates require an If-Match header"},
+	{Code: ErrorCodeIdempotencyMismatch, HTTPStatus: 422, Description: "The Idempotency-Key was used with a different request"},
+	{Code: ErrorCodeIdempotencyInProgress, HTTPStatus: 409, Description: "A request with the same Idempotency-Key is still running"},
+	{Code: ErrorCodePayloadTooLarge, HTTPStatus: 413, Description: "The request body is larger than the route accepts"},
+	{Code: ErrorCodeMaintenance, HTTPStatus: 503, Description: "The route is under maintenance"},
+	{Code: ErrorCodeReadOnly, HTTPStatus: 503, Description: "The route is read-only for now"},
+	{Code: ErrorCodeFeatureDisabled, HTTPStatus: 403, Description: "The feature is switched off"},
+}
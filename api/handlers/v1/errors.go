@@ -0,0 +1,182 @@
+package v1
+
+import (
+	"encoding/json"
+	"errors"
+	"myproject/admin-api-gateway/api/models"
+	"myproject/admin-api-gateway/pkg/i18n"
+	"myproject/admin-api-gateway/pkg/logger"
+	"myproject/admin-api-gateway/pkg/requestid"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	validation "github.com/go-ozzo/ozzo-validation/v3"
+)
+
+// NewError builds the error envelope for the request, the message is
+// translated to the language of the request
+func NewError(c *gin.Context, code, message string, details ...models.ErrorDetail) models.ResponseError {
+	return models.ResponseError{
+		Error: models.StandardErrorModel{
+			Code:      code,
+			Message:   translate(c, message),
+			Details:   details,
+			RequestID: requestid.FromContext(c.Request.Context()),
+		},
+	}
+}
+
+// AbortWithError sends the error envelope with the HTTP status and stops
+// the handler chain. Handlers and middleware respond with errors only
+// through it, so that every error has the same shape.
+func AbortWithError(c *gin.Context, httpStatus int, code, message string, details ...models.ErrorDetail) {
+	c.AbortWithStatusJSON(httpStatus, NewError(c, code, message, details...))
+}
+
+// AbortWithFieldErrors sends a 400 error envelope naming the rejected fields
+func AbortWithFieldErrors(c *gin.Context, code, message string, fields map[string]models.ValidationError) {
+	resp := NewError(c, code, message)
+	resp.Error.Fields = fields
+	c.AbortWithStatusJSON(http.StatusBadRequest, resp)
+}
+
+// List Error Codes
+// @Router /v1/errors [get]
+// @Summary list error codes
+// @Tags Errors
+// @Description Lists the codes sent in the "code" field of error responses. Every error response has the shape
+// @Description {"error": {"code", "message", "details": [{"field", "description"}], "fields": {"<path>": {"rule", "message", "user_message"}}, "request_id"}}
+// @Produce json
+// @Success 200 {array} models.ErrorCatalogEntry
+func (h *handlerV1) ListErrorCodes(c *gin.Context) {
+	c.JSON(http.StatusOK, ErrorCatalog)
+}
+
+// NoRoute answers requests to unknown routes
+func NoRoute(c *gin.Context) {
+	AbortWithError(c, http.StatusNotFound, ErrorCodeNotFound, "Route not found")
+}
+
+// Recovery answers requests whose handler panicked, gin has logged the
+// panic by then
+func Recovery(c *gin.Context, _ any) {
+	AbortWithError(c, http.StatusInternalServerError, ErrorCodeInternalServerError, "Sorry, try again")
+}
+
+// handleBindErr reports a request body that could not be decoded, naming
+// the field when its value has the wrong JSON type
+func handleBindErr(c *gin.Context, log logger.Logger, err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		AbortWithFieldErrors(c, ErrorCodeInvalidJSON, "The request body has fields of the wrong type", map[string]models.ValidationError{
+			typeErr.Field: fieldError(c, typeErr.Field, models.RuleType, jsonTypeMessage(typeErr.Type)),
+		})
+	} else {
+		AbortWithError(c, http.StatusBadRequest, ErrorCodeInvalidJSON, "The request body is not valid JSON")
+	}
+	logger.WithContext(log, c.Request.Context()).Warn("failed to bind request body", logger.Error(err))
+
+	return true
+}
+
+// handleValidationErr reports the fields rejected by the Validate method of
+// a request model
+func handleValidationErr(c *gin.Context, log logger.Logger, err error) bool {
+	if err == nil {
+		return false
+	}
+
+	errs, ok := err.(validation.Errors)
+	if !ok {
+		return handleInternalServerErrorWithMessage(c, log, err, "failed to validate request body")
+	}
+
+	fields := make(map[string]models.ValidationError)
+	flattenValidationErrors(c, "", errs, fields)
+	AbortWithFieldErrors(c, ErrorValidationError, "The request body failed validation", fields)
+	logger.WithContext(log, c.Request.Context()).Warn("request body failed validation", logger.Error(err))
+
+	return true
+}
+
+// flattenValidationErrors keys the errors of nested structs and slices by
+// their dotted path, e.g. "routes.0"
+func flattenValidationErrors(c *gin.Context, prefix string, errs validation.Errors, fields map[string]models.ValidationError) {
+	for key, err := range errs {
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		if nested, ok := err.(validation.Errors); ok {
+			flattenValidationErrors(c, key, nested, fields)
+			continue
+		}
+
+		rule := models.RuleInvalid
+		var ruleErr models.RuleError
+		if errors.As(err, &ruleErr) {
+			rule = ruleErr.Rule
+		}
+		fields[key] = fieldError(c, key, rule, err.Error())
+	}
+}
+
+// fieldError builds the error of one field in the language of the request
+func fieldError(c *gin.Context, path, rule, message string) models.ValidationError {
+	message = translate(c, message)
+	return models.ValidationError{
+		Rule:        rule,
+		Message:     message,
+		UserMessage: translate(c, fieldLabel(path)) + ": " + message,
+	}
+}
+
+// fieldLabel turns the JSON path of a field into a label for humans,
+// "routes.0" becomes "Routes" and "first_name" becomes "First name"
+func fieldLabel(path string) string {
+	parts := strings.Split(path, ".")
+	name := parts[0]
+	for i := len(parts) - 1; i >= 0; i-- {
+		if _, err := strconv.Atoi(parts[i]); err != nil {
+			name = parts[i]
+			break
+		}
+	}
+
+	name = strings.ReplaceAll(name, "_", " ")
+	if name == "" {
+		return name
+	}
+	return strings.ToUpper(name[:1]) + name[1:]
+}
+
+// jsonTypeMessage names the JSON type a Go type is decoded from
+func jsonTypeMessage(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.String:
+		return "must be a string"
+	case reflect.Bool:
+		return "must be a boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return "must be a number"
+	case reflect.Slice, reflect.Array:
+		return "must be an array"
+	case reflect.Ptr:
+		return jsonTypeMessage(t.Elem())
+	default:
+		return "must be an object"
+	}
+}
+
+// translate translates message to the language negotiated for the request
+func translate(c *gin.Context, message string) string {
+	return i18n.T(i18n.FromContext(c.Request.Context()), message)
+}
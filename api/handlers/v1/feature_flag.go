@@ -0,0 +1,154 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"myproject/admin-api-gateway/api/handlers/tokens"
+	"myproject/admin-api-gateway/api/models"
+	"myproject/admin-api-gateway/pkg/logger"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// List Feature Flags
+// @Router /v1/admin/flags [get]
+// @Security BearerAuth
+// @Summary list feature flags
+// @Tags Admin
+// @Description Lists the stored feature flags, enabled or not
+// @Produce json
+// @Success 200 {object} models.ListFeatureFlags
+// @Failure 401 {object} models.ResponseError
+// @Failure 403 {object} models.ResponseError
+// @Failure 500 {object} models.ResponseError
+func (h *handlerV1) ListFeatureFlags(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), time.Second*time.Duration(h.cfg.CtxTimeout))
+	defer cancel()
+
+	flags, err := h.featureFlags.List(ctx)
+	if handleInternalServerErrorWithMessage(c, h.log, err, "failed to list feature flags") {
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ListFeatureFlags{
+		Count: len(flags),
+		Flags: flags,
+	})
+}
+
+// Set Feature Flag
+// @Router /v1/admin/flags/{name} [put]
+// @Security BearerAuth
+// @Summary set feature flag
+// @Tags Admin
+// @Description Creates or replaces a feature flag. "maintenance" rejects every request and "read_only" rejects mutating
+// @Description requests to the given route prefixes, or to the whole API without routes. "registration_closed" and
+// @Description "doctor_registration_closed" disable self-registration. Other gateway instances pick changes up within seconds.
+// @Accept json
+// @Produce json
+// @Param name path string true "name"
+// @Param flag body models.FeatureFlagReq true "flag"
+// @Success 200 {object} models.FeatureFlag
+// @Failure 400 {object} models.ResponseError
+// @Failure 401 {object} models.ResponseError
+// @Failure 403 {object} models.ResponseError
+// @Failure 500 {object} models.ResponseError
+func (h *handlerV1) SetFeatureFlag(c *gin.Context) {
+	var body models.FeatureFlagReq
+
+	name := c.Param("name")
+	if !models.ValidFlagName(name) {
+		handleBadRequestErrWithMessage(c, h.log, errors.New("flag name should consist of lowercase letters, digits and underscores"), ErrorCodeInvalidURL)
+		return
+	}
+
+	err := c.ShouldBindJSON(&body)
+	if handleBindErr(c, h.log, err) {
+		return
+	}
+
+	err = body.Validate()
+	if handleValidationErr(c, h.log, err) {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), time.Second*time.Duration(h.cfg.CtxTimeout))
+	defer cancel()
+
+	flag := &models.FeatureFlag{
+		Name:      name,
+		Enabled:   body.Enabled,
+		Message:   body.Message,
+		Routes:    body.Routes,
+		UpdatedBy: c.GetString(tokens.SubjectKey),
+		UpdatedAt: time.Now().UTC().Format(time.RFC3339),
+	}
+	err = h.featureFlags.Set(ctx, flag)
+	if handleInternalServerErrorWithMessage(c, h.log, err, "failed to set feature flag") {
+		return
+	}
+	h.refreshFlags(ctx, c)
+
+	logger.WithContext(h.log, c.Request.Context()).Warn("feature flag changed",
+		logger.String("flag", name), logger.Bool("enabled", flag.Enabled), logger.String("by", flag.UpdatedBy))
+
+	c.JSON(http.StatusOK, flag)
+}
+
+// Delete Feature Flag
+// @Router /v1/admin/flags/{name} [delete]
+// @Security BearerAuth
+// @Summary delete feature flag
+// @Tags Admin
+// @Description Removes a feature flag, which then counts as disabled
+// @Produce json
+// @Param name path string true "name"
+// @Success 200 {object} models.Status
+// @Failure 401 {object} models.ResponseError
+// @Failure 403 {object} models.ResponseError
+// @Failure 404 {object} models.ResponseError
+// @Failure 500 {object} models.ResponseError
+func (h *handlerV1) DeleteFeatureFlag(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), time.Second*time.Duration(h.cfg.CtxTimeout))
+	defer cancel()
+
+	deleted, err := h.featureFlags.Delete(ctx, c.Param("name"))
+	if handleInternalServerErrorWithMessage(c, h.log, err, "failed to delete feature flag") {
+		return
+	}
+	if !deleted {
+		AbortWithError(c, http.StatusNotFound, ErrorCodeNotFound, "Feature flag not found")
+		return
+	}
+	h.refreshFlags(ctx, c)
+
+	c.JSON(http.StatusOK, models.Status{
+		Message: translate(c, "Feature flag deleted"),
+	})
+}
+
+// refreshFlags applies a change to this instance right away instead of
+// waiting for the next background refresh
+func (h *handlerV1) refreshFlags(ctx context.Context, c *gin.Context) {
+	if err := h.flags.Refresh(ctx); err != nil {
+		logger.WithContext(h.log, c.Request.Context()).Warn("cannot refresh feature flags", logger.Error(err))
+	}
+}
+
+// featureDisabled responds with a 403 and returns true when the flag that
+// switches the feature off is enabled
+func (h *handlerV1) featureDisabled(c *gin.Context, flagName, message string) bool {
+	flag, ok := h.flags.Get(flagName)
+	if !ok {
+		return false
+	}
+
+	if flag.Message != "" {
+		message = flag.Message
+	}
+	AbortWithError(c, http.StatusForbidden, ErrorCodeFeatureDisabled, message)
+
+	return true
+}
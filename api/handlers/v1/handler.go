@@ -1,20 +1,30 @@
 package v1
 
 import (
+	"database/sql"
 	"errors"
 	"fmt"
 	"myproject/admin-api-gateway/api/handlers/tokens"
 	"myproject/admin-api-gateway/api/models"
 	"myproject/admin-api-gateway/config"
+	"myproject/admin-api-gateway/pkg/audit"
+	"myproject/admin-api-gateway/pkg/cursor"
+	"myproject/admin-api-gateway/pkg/etag"
+	"myproject/admin-api-gateway/pkg/featureflag"
 	"myproject/admin-api-gateway/pkg/logger"
 	grpcClient "myproject/admin-api-gateway/services"
 	"myproject/admin-api-gateway/storage/postgresrepo"
 	"myproject/admin-api-gateway/storage/repo"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/casbin/casbin/v2"
 	"github.com/gin-gonic/gin"
+	"github.com/spf13/cast"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 type handlerV1 struct {
@@ -22,9 +32,14 @@ type handlerV1 struct {
 	log             logger.Logger
 	serviceManager  grpcClient.IServiceManager
 	cfg             config.Config
+	config          *config.Watcher
 	jwtHandler      tokens.JWTHandler
 	casbin          *casbin.Enforcer
 	postgres        postgresrepo.AdminStorageI
+	featureFlags    repo.FeatureFlagsI
+	flags           *featureflag.Flags
+	auditStorage    postgresrepo.AuditStorageI
+	cursors         *cursor.Signer
 }
 
 type HandlerV1Config struct {
@@ -32,9 +47,13 @@ type HandlerV1Config struct {
 	Log             logger.Logger
 	ServiceManager  grpcClient.IServiceManager
 	Cfg             config.Config
+	Config          *config.Watcher
 	JwtHandler      tokens.JWTHandler
 	Casbin          *casbin.Enforcer
 	Postgres        postgresrepo.AdminStorageI
+	FeatureFlags    repo.FeatureFlagsI
+	Flags           *featureflag.Flags
+	AuditStorage    postgresrepo.AuditStorageI
 }
 
 func New(h *HandlerV1Config) *handlerV1 {
@@ -43,27 +62,67 @@ func New(h *HandlerV1Config) *handlerV1 {
 		log:             h.Log,
 		serviceManager:  h.ServiceManager,
 		cfg:             h.Cfg,
+		config:          h.Config,
 		jwtHandler:      h.JwtHandler,
 		casbin:          h.Casbin,
 		postgres:        h.Postgres,
+		featureFlags:    h.FeatureFlags,
+		flags:           h.Flags,
+		auditStorage:    h.AuditStorage,
+		cursors:         cursor.NewSigner(h.Cfg.SignInKey, time.Duration(h.Cfg.CursorTTL)*time.Second),
 	}
 }
 
+// Error codes sent in the "code" field of the error envelope. The trailing
+// comment of each code holds its usual HTTP status and description, the
+// error catalog is generated from them: run go generate after a change.
+//
+//go:generate go run ../../../scripts/errcatalog -in handler.go -out error_catalog.go
 const (
-	ErrorCodeInvalidURL          = "INVALID_URL"
-	ErrorCodeInvalidJSON         = "INVALID_JSON"
-	ErrorCodeInvalidParams       = "INVALID_PARAMS"
-	ErrorCodeInternalServerError = "INTERNAL_SERVER_ERROR"
-	ErrorCodeUnauthorized        = "UNAUTHORIZED"
-	ErrorCodeAlreadyExists       = "ALREADY_EXISTS"
-	ErrorCodeNotFound            = "NOT_FOUND"
-	ErrorCodeInvalidCode         = "INVALID_CODE"
-	ErrorBadRequest              = "BAD_REQUEST"
-	ErrorInvalidCredentials      = "INVALID_CREDENTIALS"
-	StatusMethodNotAllowed       = "METHOD_NOT_ALLOWED"
-	ErrorValidationError         = "VALIDATION_ERROR"
+	ErrorCodeInvalidURL          = "INVALID_URL"           // 400 A path parameter is malformed
+	ErrorCodeInvalidJSON         = "INVALID_JSON"          // 400 The request body is not valid JSON, "fields" names fields of the wrong type
+	ErrorCodeInvalidParams       = "INVALID_PARAMS"        // 400 A query or path parameter is missing or invalid
+	ErrorCodeInternalServerError = "INTERNAL_SERVER_ERROR" // 500 Unexpected failure, the request id identifies it in the logs
+	ErrorCodeUnauthorized        = "UNAUTHORIZED"          // 401 The access token is missing, invalid or expired
+	ErrorCodeAlreadyExists       = "ALREADY_EXISTS"        // 409 A resource with the same unique fields exists
+	ErrorCodeNotFound            = "NOT_FOUND"             // 404 The resource or route does not exist
+	ErrorCodeInvalidCode         = "INVALID_CODE"          // 400 The verification code is wrong
+	ErrorBadRequest              = "BAD_REQUEST"           // 400 The request cannot be processed as sent
+	ErrorInvalidCredentials      = "INVALID_CREDENTIALS"   // 400 The username or password is wrong
+	StatusMethodNotAllowed       = "METHOD_NOT_ALLOWED"    // 405 The role of the caller may not use this route
+	ErrorValidationError         = "VALIDATION_ERROR"      // 400 The request body failed validation, "fields" names the rejected fields
+	ErrorCodeTooManyRequests     = "TOO_MANY_REQUESTS"     // 429 Rate limit exceeded, see the Retry-After header
+	ErrorCodeForbidden           = "FORBIDDEN"             // 403 The caller may not access this resource
+	ErrorCodeInvalidArgument     = "INVALID_ARGUMENT"      // 400 A backend service rejected an argument
+	ErrorCodeServiceUnavailable  = "SERVICE_UNAVAILABLE"   // 503 A backend service is unavailable, retry later
+	ErrorCodeGatewayTimeout      = "GATEWAY_TIMEOUT"       // 504 A backend service did not answer in time
+	ErrorCodePreconditionFailed  = "PRECONDITION_FAILED"   // 412 The If-Match ETag does not match the current version
+	ErrorCodePreconditionNeeded  = "PRECONDITION_REQUIRED" // 428 Updates require an If-Match header
+
+	ErrorCodeIdempotencyMismatch   = "IDEMPOTENCY_KEY_REUSED"      // 422 The Idempotency-Key was used with a different request
+	ErrorCodeIdempotencyInProgress = "IDEMPOTENCY_KEY_IN_PROGRESS" // 409 A request with the same Idempotency-Key is still running
+	ErrorCodePayloadTooLarge       = "PAYLOAD_TOO_LARGE"           // 413 The request body is larger than the route accepts
+
+	ErrorCodeMaintenance     = "MAINTENANCE"      // 503 The route is under maintenance
+	ErrorCodeReadOnly        = "READ_ONLY"        // 503 The route is read-only for now
+	ErrorCodeFeatureDisabled = "FEATURE_DISABLED" // 403 The feature is switched off
 )
 
+// grpcErrors maps the gRPC status codes a backend may return to the HTTP
+// status and the error code sent to clients. Codes missing here are 500s.
+var grpcErrors = map[codes.Code]struct {
+	httpStatus int
+	errorCode  string
+}{
+	codes.NotFound:         {http.StatusNotFound, ErrorCodeNotFound},
+	codes.AlreadyExists:    {http.StatusConflict, ErrorCodeAlreadyExists},
+	codes.InvalidArgument:  {http.StatusBadRequest, ErrorCodeInvalidArgument},
+	codes.PermissionDenied: {http.StatusForbidden, ErrorCodeForbidden},
+	codes.Unauthenticated:  {http.StatusUnauthorized, ErrorCodeUnauthorized},
+	codes.DeadlineExceeded: {http.StatusGatewayTimeout, ErrorCodeGatewayTimeout},
+	codes.Unavailable:      {http.StatusServiceUnavailable, ErrorCodeServiceUnavailable},
+}
+
 func ParsePageQueryParam(c *gin.Context) (int, error) {
 	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
 	if err != nil {
@@ -97,13 +156,8 @@ func ParseLimitQueryParam(c *gin.Context) (int, error) {
 
 func handleBadRequestErrWithMessage(c *gin.Context, log logger.Logger, err error, status string) bool {
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.ResponseError{
-			Error: models.StandardErrorModel{
-				Status:  status,
-				Message: err.Error(),
-			},
-		})
-		log.Error(err.Error(), logger.Error(err))
+		AbortWithError(c, http.StatusBadRequest, status, err.Error())
+		logger.WithContext(log, c.Request.Context()).Error(err.Error(), logger.Error(err))
 		return true
 	}
 	return false
@@ -111,19 +165,150 @@ func handleBadRequestErrWithMessage(c *gin.Context, log logger.Logger, err error
 
 func handleInternalServerErrorWithMessage(c *gin.Context, log logger.Logger, err error, message string) bool {
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ResponseError{
-			Error: models.ServerError{
-				Status:  ErrorCodeInternalServerError,
-				Message: "Sorry, try again",
-			},
-		})
-		log.Error(message, logger.Error(err))
+		AbortWithError(c, http.StatusInternalServerError, ErrorCodeInternalServerError, "Sorry, try again")
+		logger.WithContext(log, c.Request.Context()).Error(message, logger.Error(err))
 		return true
 	}
 
 	return false
 }
 
+// handlePostgresErrWithMessage translates an error returned by the database
+// of the gateway, a missing row is a 404 like codes.NotFound of a backend
+func handlePostgresErrWithMessage(c *gin.Context, log logger.Logger, err error, message string) bool {
+	if errors.Is(err, sql.ErrNoRows) {
+		AbortWithError(c, http.StatusNotFound, ErrorCodeNotFound, "Resource not found")
+		logger.WithContext(log, c.Request.Context()).Warn(message, logger.Error(err))
+		return true
+	}
+
+	return handleInternalServerErrorWithMessage(c, log, err, message)
+}
+
+// handleGrpcErrWithMessage translates an error returned by a backend service
+// into the matching HTTP response. Errors without a gRPC status may come from
+// the database of the gateway, anything else unknown is a 500.
+func handleGrpcErrWithMessage(c *gin.Context, log logger.Logger, err error, message string) bool {
+	if err == nil {
+		return false
+	}
+
+	st, _ := status.FromError(err)
+	mapped, ok := grpcErrors[st.Code()]
+	if !ok {
+		return handlePostgresErrWithMessage(c, log, err, message)
+	}
+
+	AbortWithError(c, mapped.httpStatus, mapped.errorCode, grpcErrorMessage(st, mapped.httpStatus), grpcErrorDetails(st)...)
+
+	log = logger.WithContext(log, c.Request.Context())
+	if mapped.httpStatus >= http.StatusInternalServerError {
+		log.Error(message, logger.Error(err))
+	} else {
+		log.Warn(message, logger.Error(err))
+	}
+
+	return true
+}
+
+// grpcErrorMessage keeps the backend message for client errors only, server
+// side failures get a generic message so that internals do not leak
+func grpcErrorMessage(st *status.Status, httpStatus int) string {
+	switch {
+	case httpStatus == http.StatusServiceUnavailable:
+		return "Service is temporarily unavailable, try again later"
+	case httpStatus == http.StatusGatewayTimeout:
+		return "Service did not respond in time, try again later"
+	case st.Message() != "":
+		return st.Message()
+	}
+
+	return http.StatusText(httpStatus)
+}
+
+// grpcErrorDetails collects the field violations attached to an InvalidArgument status
+func grpcErrorDetails(st *status.Status) []models.ErrorDetail {
+	var details []models.ErrorDetail
+	for _, detail := range st.Details() {
+		badRequest, ok := detail.(*errdetails.BadRequest)
+		if !ok {
+			continue
+		}
+		for _, violation := range badRequest.GetFieldViolations() {
+			details = append(details, models.ErrorDetail{
+				Field:       violation.GetField(),
+				Description: violation.GetDescription(),
+			})
+		}
+	}
+
+	return details
+}
+
+// writeWithETag sends the response tagged with its ETag, or 304 without a
+// body when the client already has this version. The response is the view
+// of the caller, so fields it does not see never change the ETag.
+func writeWithETag(c *gin.Context, response interface{}) {
+	tag := etag.Of(response)
+	if tag != "" {
+		c.Header("ETag", tag)
+	}
+
+	if etag.Match(c.GetHeader("If-None-Match"), tag) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// checkIfMatch compares If-Match with the ETag of the current version of the
+// resource before an update, current returns it in the view the GET of the
+// caller returns so the tags are comparable. It responds with 428 when the header is required
+// but missing and with 412 when the resource changed since the client read it.
+// The backends have no conditional updates, so a concurrent write between
+// the check and the update is still possible, just within a much smaller window.
+func (h *handlerV1) checkIfMatch(c *gin.Context, current func() (interface{}, error)) bool {
+	ifMatch := c.GetHeader("If-Match")
+	if ifMatch == "" {
+		if !h.cfg.RequireIfMatch {
+			// the current version is only needed for the audit log here,
+			// a failed lookup is left for the update itself to report
+			if audit.FromContext(c.Request.Context()) != nil {
+				if resource, err := current(); err == nil {
+					audit.SetBefore(c.Request.Context(), resource)
+				}
+			}
+			return true
+		}
+		AbortWithError(c, http.StatusPreconditionRequired, ErrorCodePreconditionNeeded, "If-Match header with the ETag of the resource is required")
+		return false
+	}
+
+	resource, err := current()
+	if handleGrpcErrWithMessage(c, h.log, err, "error while getting the current version of the resource") {
+		return false
+	}
+	audit.SetBefore(c.Request.Context(), resource)
+
+	if !etag.MatchStrong(ifMatch, etag.Of(resource)) {
+		AbortWithError(c, http.StatusPreconditionFailed, ErrorCodePreconditionFailed, "Resource was changed by someone else, fetch it again and retry")
+		return false
+	}
+
+	return true
+}
+
+// auditChange completes the audit entry of the request with the id of the
+// changed resource and its new state
+func auditChange(c *gin.Context, resourceID interface{}, after interface{}) {
+	ctx := c.Request.Context()
+	if entry := audit.FromContext(ctx); entry != nil {
+		audit.SetResource(ctx, entry.ResourceType, cast.ToString(resourceID))
+		audit.SetAfter(ctx, after)
+	}
+}
+
 func checkMethod(method string) bool {
 	methods := []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS", "HEAD", "CONNECT"}
 
@@ -134,4 +319,4 @@ func checkMethod(method string) bool {
 	}
 
 	return false
-}
\ No newline at end of file
+}
@@ -0,0 +1,70 @@
+package v1
+
+import (
+	"myproject/admin-api-gateway/api/handlers/tokens"
+	"myproject/admin-api-gateway/api/models"
+	"myproject/admin-api-gateway/pkg/audit"
+	"myproject/admin-api-gateway/pkg/i18n"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Get Language
+// @Router /v1/profile/language [get]
+// @Security BearerAuth
+// @Summary get language
+// @Tags Profile
+// @Description Returns the language messages are sent to the caller in: the saved one, or else the one negotiated from Accept-Language
+// @Produce json
+// @Success 200 {object} models.Language
+// @Failure 401 {object} models.ResponseError
+func (h *handlerV1) GetLanguage(c *gin.Context) {
+	c.JSON(http.StatusOK, models.Language{
+		Language: i18n.FromContext(c.Request.Context()),
+	})
+}
+
+// Update Language
+// @Router /v1/profile/language [put]
+// @Security BearerAuth
+// @Summary update language
+// @Tags Profile
+// @Description Saves the language messages and emails are sent to the caller in, it takes precedence over Accept-Language
+// @Accept json
+// @Produce json
+// @Param language body models.Language true "language"
+// @Success 200 {object} models.Language
+// @Failure 400 {object} models.ResponseError
+// @Failure 401 {object} models.ResponseError
+// @Failure 500 {object} models.ResponseError
+func (h *handlerV1) UpdateLanguage(c *gin.Context) {
+	var body models.Language
+
+	subject := c.GetString(tokens.SubjectKey)
+	if subject == "" {
+		AbortWithError(c, http.StatusUnauthorized, ErrorCodeUnauthorized, "Log in to choose a language")
+		return
+	}
+
+	err := c.ShouldBindJSON(&body)
+	if handleBindErr(c, h.log, err) {
+		return
+	}
+
+	err = body.Validate()
+	if handleValidationErr(c, h.log, err) {
+		return
+	}
+
+	err = h.inMemoryStorage.Set(c.Request.Context(), i18n.PreferenceKey(subject), body.Language)
+	if handleInternalServerErrorWithMessage(c, h.log, err, "failed to save language preference") {
+		return
+	}
+	audit.SetResource(c.Request.Context(), "language", subject)
+	audit.SetAfter(c.Request.Context(), body)
+
+	c.Request = c.Request.WithContext(i18n.NewContext(c.Request.Context(), body.Language))
+	c.Header(i18n.Header, body.Language)
+	c.JSON(http.StatusOK, body)
+}
@@ -0,0 +1,329 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"myproject/admin-api-gateway/api/models"
+	pb "myproject/admin-api-gateway/genproto/healthcare-service"
+	pbu "myproject/admin-api-gateway/genproto/user-service"
+	"myproject/admin-api-gateway/pkg/cursor"
+	"myproject/admin-api-gateway/pkg/listquery"
+	"myproject/admin-api-gateway/pkg/logger"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	validation "github.com/go-ozzo/ozzo-validation/v3"
+)
+
+// scanPageSize is the page size used to read a whole backend list
+const scanPageSize = 100
+
+var (
+	doctorsSpec = listquery.Spec{
+		Filters: map[string]listquery.Filter{
+			"department_id": {Kind: listquery.Int},
+			"gender":        {Kind: listquery.String, Values: []string{"male", "female"}},
+			"is_verified":   {Kind: listquery.Bool},
+		},
+		Sorts: []string{"full_name", "work_years", "created_at"},
+	}
+	doctorsFields = listquery.Fields[*pb.Doctor]{
+		"gender":      func(d *pb.Doctor) interface{} { return d.Gender },
+		"is_verified": func(d *pb.Doctor) interface{} { return d.IsVerified },
+		"full_name":   func(d *pb.Doctor) interface{} { return d.FullName },
+		"work_years":  func(d *pb.Doctor) interface{} { return d.WorkYears },
+		"created_at":  func(d *pb.Doctor) interface{} { return d.CreatedAt },
+	}
+
+	usersSpec = listquery.Spec{
+		Filters: map[string]listquery.Filter{
+			"search": {Kind: listquery.String},
+		},
+		Sorts: []string{"first_name", "last_name", "created_at"},
+	}
+	usersFields = listquery.Fields[*pbu.User]{
+		"first_name": func(u *pbu.User) interface{} { return u.FirstName },
+		"last_name":  func(u *pbu.User) interface{} { return u.LastName },
+		"created_at": func(u *pbu.User) interface{} { return u.CreatedAt },
+	}
+
+	departmentsSpec = listquery.Spec{
+		Sorts: []string{"name", "created_at"},
+	}
+	departmentsFields = listquery.Fields[*pb.Department]{
+		"name":       func(d *pb.Department) interface{} { return d.Name },
+		"created_at": func(d *pb.Department) interface{} { return d.CreatedAt },
+	}
+
+	adminsSpec = listquery.Spec{
+		Filters: map[string]listquery.Filter{
+			"role": {Kind: listquery.String, Values: []string{"admin", "superadmin"}},
+		},
+		Sorts:  []string{"full_name", "username", "email", "age", "role", "created_at"},
+		Cursor: true,
+	}
+)
+
+// List Doctors
+// @Router /v2/doctors [get]
+// @Security BearerAuth
+// @Summary list doctors
+// @Tags v2
+// @Description Lists doctors. sort takes comma separated fields, prefixed with - for descending order: full_name, work_years, created_at
+// @Produce json
+// @Param department_id query int false "department id"
+// @Param gender query string false "gender" Enums(male, female)
+// @Param is_verified query bool false "verified doctors only"
+// @Param sort query string false "sort, e.g. -created_at"
+// @Param page query int false "page" default(1)
+// @Param limit query int false "limit" default(10)
+// @Success 200 {object} models.Page{data=[]models.DoctorResp}
+// @Failure 400 {object} models.ResponseError
+// @Failure 401 {object} models.ResponseError
+// @Failure 500 {object} models.ResponseError
+func (h *handlerV1) ListDoctorsV2(c *gin.Context) {
+	query, ok := h.parseListQuery(c, doctorsSpec)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), time.Second*time.Duration(h.cfg.CtxTimeout))
+	defer cancel()
+
+	fetch := func(ctx context.Context, page, limit int64) ([]*pb.Doctor, int64, error) {
+		var (
+			resp *pb.ListDoctors
+			err  error
+		)
+		if departmentID, ok := query.Filter("department_id"); ok {
+			id, _ := strconv.ParseInt(departmentID, 10, 64)
+			resp, err = h.serviceManager.HealthCareService().GetAllDoctorsByDepartmentId(ctx, &pb.GetRequest{Page: page, Limit: limit, Id: id})
+		} else {
+			resp, err = h.serviceManager.HealthCareService().GetAllDoctors(ctx, &pb.GetAll{Page: page, Limit: limit})
+		}
+		if err != nil {
+			return nil, 0, err
+		}
+		return resp.Doctors, resp.Count, nil
+	}
+
+	doctors, total, err := listFromBackend(ctx, h, query, doctorsFields, fetch)
+	if handleGrpcErrWithMessage(c, h.log, err, "failed to list doctors") {
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Page{Data: viewerOf(c).doctors(doctors), Total: total, Page: query.Page, Limit: query.Limit})
+}
+
+// List Users
+// @Router /v2/users [get]
+// @Security BearerAuth
+// @Summary list users
+// @Tags v2
+// @Description Lists users. sort takes comma separated fields, prefixed with - for descending order: first_name, last_name, created_at
+// @Produce json
+// @Param search query string false "text searched for by the user service"
+// @Param sort query string false "sort, e.g. last_name,first_name"
+// @Param page query int false "page" default(1)
+// @Param limit query int false "limit" default(10)
+// @Success 200 {object} models.Page{data=[]models.UserResp}
+// @Failure 400 {object} models.ResponseError
+// @Failure 401 {object} models.ResponseError
+// @Failure 500 {object} models.ResponseError
+func (h *handlerV1) ListUsersV2(c *gin.Context) {
+	query, ok := h.parseListQuery(c, usersSpec)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), time.Second*time.Duration(h.cfg.CtxTimeout))
+	defer cancel()
+
+	search, _ := query.Filter("search")
+	fetch := func(ctx context.Context, page, limit int64) ([]*pbu.User, int64, error) {
+		resp, err := h.serviceManager.UserService().GetAllUsers(ctx, &pbu.ListUsersReq{Page: page, Limit: limit, Filter: search})
+		if err != nil {
+			return nil, 0, err
+		}
+		return resp.Users, resp.Count, nil
+	}
+
+	users, total, err := listFromBackend(ctx, h, query, usersFields, fetch)
+	if handleGrpcErrWithMessage(c, h.log, err, "failed to list users") {
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Page{Data: viewerOf(c).users(users), Total: total, Page: query.Page, Limit: query.Limit})
+}
+
+// List Departments
+// @Router /v2/departments [get]
+// @Security BearerAuth
+// @Summary list departments
+// @Tags v2
+// @Description Lists departments. sort takes comma separated fields, prefixed with - for descending order: name, created_at
+// @Produce json
+// @Param sort query string false "sort, e.g. name"
+// @Param page query int false "page" default(1)
+// @Param limit query int false "limit" default(10)
+// @Success 200 {object} models.Page{data=[]models.DepartmentResp}
+// @Failure 400 {object} models.ResponseError
+// @Failure 401 {object} models.ResponseError
+// @Failure 500 {object} models.ResponseError
+func (h *handlerV1) ListDepartmentsV2(c *gin.Context) {
+	query, ok := h.parseListQuery(c, departmentsSpec)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), time.Second*time.Duration(h.cfg.CtxTimeout))
+	defer cancel()
+
+	fetch := func(ctx context.Context, page, limit int64) ([]*pb.Department, int64, error) {
+		resp, err := h.serviceManager.HealthCareService().GetAllDepartments(ctx, &pb.GetAll{Page: page, Limit: limit})
+		if err != nil {
+			return nil, 0, err
+		}
+		return resp.Departments, resp.Count, nil
+	}
+
+	departments, total, err := listFromBackend(ctx, h, query, departmentsFields, fetch)
+	if handleGrpcErrWithMessage(c, h.log, err, "failed to list departments") {
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Page{Data: departments, Total: total, Page: query.Page, Limit: query.Limit})
+}
+
+// List Admins
+// @Router /v2/admins [get]
+// @Security BearerAuth
+// @Summary list admins
+// @Tags v2
+// @Description Lists admins. sort takes comma separated fields, prefixed with - for descending order: full_name, username, email, age, role, created_at
+// @Description
+// @Description With a cursor parameter, empty for the first page, admins are listed newest first by cursor instead of by page:
+// @Description the response is a models.CursorPage holding the next and prev cursors, also sent as a Link header, and page and sort are rejected.
+// @Produce json
+// @Param role query string false "role" Enums(admin, superadmin)
+// @Param sort query string false "sort, e.g. -age"
+// @Param page query int false "page" default(1)
+// @Param limit query int false "limit" default(10)
+// @Param cursor query string false "cursor, empty for the first page"
+// @Success 200 {object} models.Page{data=[]models.Admin}
+// @Header 200 {string} Link "pages after and before, with a cursor parameter"
+// @Failure 400 {object} models.ResponseError
+// @Failure 401 {object} models.ResponseError
+// @Failure 500 {object} models.ResponseError
+func (h *handlerV1) ListAdminsV2(c *gin.Context) {
+	query, ok := h.parseListQuery(c, adminsSpec)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), time.Second*time.Duration(h.cfg.CtxTimeout))
+	defer cancel()
+
+	if query.CursorMode {
+		h.scrollAdmins(ctx, c, query)
+		return
+	}
+
+	admins, total, err := h.postgres.QueryAdmins(ctx, query)
+	if handleInternalServerErrorWithMessage(c, h.log, err, "failed to list admins") {
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Page{Data: viewerOf(c).admins(admins), Total: total, Page: query.Page, Limit: query.Limit})
+}
+
+// scrollAdmins answers a page of admins read by cursor
+func (h *handlerV1) scrollAdmins(ctx context.Context, c *gin.Context, query listquery.Query) {
+	var current *cursor.Cursor
+	if query.Cursor != "" {
+		decoded, err := h.cursors.Decode(query.Cursor)
+		if err != nil {
+			message := "is invalid"
+			if errors.Is(err, cursor.ErrExpired) {
+				message = "has expired, start again from the first page"
+			}
+			AbortWithFieldErrors(c, ErrorCodeInvalidParams, "The query parameters are invalid", map[string]models.ValidationError{
+				listquery.CursorParam: fieldError(c, listquery.CursorParam, models.RuleFormat, message),
+			})
+			return
+		}
+		current = decoded
+	}
+
+	rows, err := h.postgres.ScrollAdmins(ctx, query, current)
+	if handleInternalServerErrorWithMessage(c, h.log, err, "failed to list admins") {
+		return
+	}
+
+	admins, next, prev := cursor.Page(h.cursors, rows, query.Limit, current, func(admin *models.AdminReq) cursor.Position {
+		return cursor.Position{CreatedAt: admin.CreatedAt, ID: admin.Id}
+	})
+	if link := cursor.LinkHeader(c.Request.URL, listquery.CursorParam, next, prev); link != "" {
+		c.Header("Link", link)
+	}
+
+	c.JSON(http.StatusOK, models.CursorPage{Data: viewerOf(c).admins(admins), Limit: query.Limit, Next: next, Prev: prev})
+}
+
+// parseListQuery reads the pagination, filter and sort parameters of a v2
+// list, answering 400 with the rejected parameters when they are invalid
+func (h *handlerV1) parseListQuery(c *gin.Context, spec listquery.Spec) (listquery.Query, bool) {
+	query, err := listquery.Parse(c.Request.URL.Query(), spec)
+	if err == nil {
+		return query, true
+	}
+
+	errs, ok := err.(validation.Errors)
+	if !ok {
+		handleInternalServerErrorWithMessage(c, h.log, err, "failed to parse list query")
+		return query, false
+	}
+
+	fields := make(map[string]models.ValidationError)
+	flattenValidationErrors(c, "", errs, fields)
+	AbortWithFieldErrors(c, ErrorCodeInvalidParams, "The query parameters are invalid", fields)
+	return query, false
+}
+
+// listFromBackend returns a page of a backend list. Backends only page, so
+// when the query filters or sorts on fields they do not know, the whole
+// list is read, up to LIST_SCAN_LIMIT items, and the gateway does the rest.
+func listFromBackend[T any](ctx context.Context, h *handlerV1, query listquery.Query, fields listquery.Fields[T],
+	fetch func(ctx context.Context, page, limit int64) ([]T, int64, error)) ([]T, int64, error) {
+	if !fields.NeedsGateway(query) {
+		items, total, err := fetch(ctx, int64(query.Page), int64(query.Limit))
+		if items == nil {
+			items = []T{}
+		}
+		return items, total, err
+	}
+
+	scanLimit := h.config.Current().ListScanLimit
+	var all []T
+	for page := int64(1); ; page++ {
+		items, total, err := fetch(ctx, page, scanPageSize)
+		if err != nil {
+			return nil, 0, err
+		}
+		all = append(all, items...)
+
+		if len(items) < scanPageSize || int64(len(all)) >= total {
+			break
+		}
+		if len(all) >= scanLimit {
+			logger.WithContext(h.log, ctx).Warn("list is longer than LIST_SCAN_LIMIT, filtering and sorting only its beginning",
+				logger.Int("scan_limit", scanLimit), logger.Int64("total", total))
+			break
+		}
+	}
+
+	items, total := listquery.Apply(all, query, fields)
+	return items, int64(total), nil
+}
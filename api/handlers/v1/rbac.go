@@ -2,6 +2,7 @@ package v1
 
 import (
 	"myproject/admin-api-gateway/api/models"
+	"myproject/admin-api-gateway/pkg/audit"
 	"net/http"
 	"strings"
 
@@ -20,8 +21,7 @@ import (
 // @Param username query string true "username"
 // @Param password query string true "password"
 // @Success 201 {object} models.RbacAllRolesResp
-// @Failure 400 string error models.Error
-// @Failure 400 string error models.Error
+// @Failure 400 {object} models.ResponseError
 func (h *handlerV1) ListRoles(c *gin.Context) {
 	var (
 		jspMarshal protojson.MarshalOptions
@@ -37,9 +37,7 @@ func (h *handlerV1) ListRoles(c *gin.Context) {
 			Roles: roles,
 		})
 	} else {
-		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
-			"message": "you cannot get all roles, provide correct username and password",
-		})
+		AbortWithError(c, http.StatusUnauthorized, ErrorCodeUnauthorized, "you cannot get all roles, provide correct username and password")
 	}
 }
 
@@ -55,8 +53,7 @@ func (h *handlerV1) ListRoles(c *gin.Context) {
 // @Param password query string true "password"
 // @Param role path string true "role"
 // @Success 201 {object} models.ListRolePolicyResp
-// @Failure 400 string error models.Error
-// @Failure 400 string error models.Error
+// @Failure 400 {object} models.ResponseError
 func (h *handlerV1) ListRolePolicies(c *gin.Context) {
 	var (
 		jspMarshal protojson.MarshalOptions
@@ -77,9 +74,7 @@ func (h *handlerV1) ListRolePolicies(c *gin.Context) {
 		}
 		c.JSON(http.StatusOK, response)
 	} else {
-		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
-			"message": "you cannot get policies of a role, provide correct username and password",
-		})
+		AbortWithError(c, http.StatusUnauthorized, ErrorCodeUnauthorized, "you cannot get policies of a role, provide correct username and password")
 	}
 }
 
@@ -95,8 +90,7 @@ func (h *handlerV1) ListRolePolicies(c *gin.Context) {
 // @Param password query string true "password"
 // @Param policy body models.AddPolicyRequest true "policy"
 // @Success 201 {object} models.SuperAdminMessage
-// @Failure 400 string error models.Error
-// @Failure 400 string error models.Error
+// @Failure 400 {object} models.ResponseError
 func (h *handlerV1) AddPolicyToRole(c *gin.Context) {
 	var (
 		jspbMarshal protojson.MarshalOptions
@@ -107,39 +101,29 @@ func (h *handlerV1) AddPolicyToRole(c *gin.Context) {
 	superAdminUsername := c.Query("username")
 	superAdminPassword := c.Query("password")
 	if superAdminPassword == "admin" && superAdminUsername == "admin" {
-		err := c.BindJSON(&body)
-		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"error":   err.Error(),
-				"message": "failed, try again",
-			})
+		err := c.ShouldBindJSON(&body)
+		if handleBindErr(c, h.log, err) {
 			return
 		}
 		body.Policy.Method = strings.ToUpper(body.Policy.Method)
 		status := checkMethod(body.Policy.Method)
 		if !status {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"error":   ErrorBadRequest,
-				"message": "invalid method",
-			})
+			AbortWithError(c, http.StatusBadRequest, ErrorCodeInvalidParams, "invalid method")
 			return
 		}
 		p := []string{body.Policy.Role, body.Policy.EndPoint, body.Policy.Method}
 		if _, err := h.casbin.AddPolicy(p); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"error":   err.Error(),
-				"message": "failed, try again",
-			})
+			AbortWithError(c, http.StatusBadRequest, ErrorBadRequest, err.Error())
 			return
 		}
 		h.casbin.SavePolicy()
+		audit.SetResource(c.Request.Context(), "policy", strings.Join(p, " "))
+		audit.SetAfter(c.Request.Context(), body.Policy)
 		c.JSON(http.StatusOK, models.SuperAdminMessage{
-			Message: "success",
+			Message: translate(c, "success"),
 		})
 	} else {
-		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
-			"message": "you cannot add policy, provide correct username and password",
-		})
+		AbortWithError(c, http.StatusUnauthorized, ErrorCodeUnauthorized, "you cannot add policy, provide correct username and password")
 	}
 }
 
@@ -155,8 +139,7 @@ func (h *handlerV1) AddPolicyToRole(c *gin.Context) {
 // @Param password query string true "password"
 // @Param policy body models.AddPolicyRequest true "policy"
 // @Success 201 {object} models.SuperAdminMessage
-// @Failure 400 string error models.Error
-// @Failure 400 string error models.Error
+// @Failure 400 {object} models.ResponseError
 func (h *handlerV1) DeletePolicy(c *gin.Context) {
 	var (
 		jspbMarshal protojson.MarshalOptions
@@ -166,29 +149,22 @@ func (h *handlerV1) DeletePolicy(c *gin.Context) {
 	superAdminUsername := c.Query("username")
 	superAdminPassword := c.Query("password")
 	if superAdminPassword == "admin" && superAdminUsername == "admin" {
-		err := c.BindJSON(&body)
-		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"error":   err.Error,
-				"message": "failed, try again",
-			})
+		err := c.ShouldBindJSON(&body)
+		if handleBindErr(c, h.log, err) {
 			return
 		}
 		p := []string{body.Policy.Role, body.Policy.EndPoint, body.Policy.Method}
 		if _, err := h.casbin.RemovePolicy(p); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"error":   err.Error,
-				"message": "failed, try again",
-			})
+			AbortWithError(c, http.StatusBadRequest, ErrorBadRequest, err.Error())
 			return
 		}
 		h.casbin.SavePolicy()
+		audit.SetResource(c.Request.Context(), "policy", strings.Join(p, " "))
+		audit.SetBefore(c.Request.Context(), body.Policy)
 		c.JSON(http.StatusOK, models.SuperAdminMessage{
-			Message: "success",
+			Message: translate(c, "success"),
 		})
 	} else {
-		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
-			"message": "you cannot delete policy, provide correct username and password",
-		})
+		AbortWithError(c, http.StatusUnauthorized, ErrorCodeUnauthorized, "you cannot delete policy, provide correct username and password")
 	}
 }
\ No newline at end of file
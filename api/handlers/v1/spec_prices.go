@@ -22,9 +22,12 @@ import (
 // @Accept json
 // @Produce json
 // @Param SpecPriceInfo body models.SpecPriceReq true "Create specialization price"
+// @Param Idempotency-Key header string false "Unique key of the request, retries with the same key are replayed"
 // @Success 201 {object} models.SpecPriceModel
-// @Failure 400 string Error models.ResponseError
-// @Failure 500 string Error models.ResponseError
+// @Failure 400 {object} models.ResponseError
+// @Failure 409 {object} models.ResponseError
+// @Failure 422 {object} models.ResponseError
+// @Failure 500 {object} models.ResponseError
 func (h *handlerV1) CreateSpecPrice(c *gin.Context) {
 	var (
 		body       models.SpecPriceReq
@@ -33,7 +36,7 @@ func (h *handlerV1) CreateSpecPrice(c *gin.Context) {
 
 	jspMarshal.UseProtoNames = true
 	err := c.ShouldBindJSON(&body)
-	if handleBadRequestErrWithMessage(c, h.log, err, ErrorCodeInvalidJSON) {
+	if handleBindErr(c, h.log, err) {
 		return
 	}
 
@@ -44,23 +47,16 @@ func (h *handlerV1) CreateSpecPrice(c *gin.Context) {
 		OfflinePrice:     body.OfflinePrice,
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second*time.Duration(h.cfg.CtxTimeout))
+	ctx, cancel := context.WithTimeout(c.Request.Context(), time.Second*time.Duration(h.cfg.CtxTimeout))
 	defer cancel()
 
 	respSpecPrice, err := h.serviceManager.HealthCareService().CreateDocSpecPrices(ctx, createReq)
-	if handleInternalServerErrorWithMessage(c, h.log, err, "error while creating a specialization price") {
+	if handleGrpcErrWithMessage(c, h.log, err, "error while creating a specialization price") {
 		return
 	}
 
-	response := models.SpecPriceModel{
-		ID:               respSpecPrice.Id,
-		DoctorId:         respSpecPrice.DoctorId,
-		SpecializationId: respSpecPrice.SpecializationId,
-		OnlinePrice:      respSpecPrice.OnlinePrice,
-		OfflinePrice:     respSpecPrice.OfflinePrice,
-		CreatedAt:        respSpecPrice.CreatedAt,
-		UpdatedAt:        respSpecPrice.UpdatedAt,
-	}
+	response := viewerOf(c).specPrice(respSpecPrice)
+	auditChange(c, response.ID, response)
 
 	c.JSON(http.StatusOK, response)
 }
@@ -74,9 +70,11 @@ func (h *handlerV1) CreateSpecPrice(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param id path int64 true "id"
+// @Param If-None-Match header string false "ETag of the version the client has"
 // @Success 201 {object} models.SpecPriceModel
-// @Failure 400 string Error models.ResponseError
-// @Failure 500 string Error models.ResponseError
+// @Failure 400 {object} models.ResponseError
+// @Failure 404 {object} models.ResponseError
+// @Failure 500 {object} models.ResponseError
 func (h *handlerV1) GetSpecPriceById(c *gin.Context) {
 	var jspMarshal protojson.MarshalOptions
 	jspMarshal.UseProtoNames = true
@@ -87,25 +85,17 @@ func (h *handlerV1) GetSpecPriceById(c *gin.Context) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second*time.Duration((h.cfg.CtxTimeout)))
+	ctx, cancel := context.WithTimeout(c.Request.Context(), time.Second*time.Duration((h.cfg.CtxTimeout)))
 	defer cancel()
 
 	respSpecPrice, err := h.serviceManager.HealthCareService().GetSpecPriceById(ctx, &pb.GetReqInt{Id: int64(idToInt)})
-	if handleInternalServerErrorWithMessage(c, h.log, err, "error while getting specializatio price by id") {
+	if handleGrpcErrWithMessage(c, h.log, err, "error while getting specializatio price by id") {
 		return
 	}
 
-	response := models.SpecPriceModel{
-		ID:               respSpecPrice.Id,
-		DoctorId:         respSpecPrice.DoctorId,
-		SpecializationId: respSpecPrice.SpecializationId,
-		OnlinePrice:      respSpecPrice.OnlinePrice,
-		OfflinePrice:     respSpecPrice.OfflinePrice,
-		CreatedAt:        respSpecPrice.CreatedAt,
-		UpdatedAt:        respSpecPrice.UpdatedAt,
-	}
+	response := viewerOf(c).specPrice(respSpecPrice)
 
-	c.JSON(http.StatusOK, response)
+	writeWithETag(c, response)
 }
 
 // Update Specialization Price
@@ -118,9 +108,13 @@ func (h *handlerV1) GetSpecPriceById(c *gin.Context) {
 // @Produce json
 // @Param id path int64 false "id"
 // @Param UserInfo body models.SpecPriceReq true "Update specialization price"
+// @Param If-Match header string false "ETag of the version being updated"
 // @Success 201 {object} models.SpecPriceModel
-// @Failure 400 string Error models.ResponseError
-// @Failure 500 string Error models.ResponseError
+// @Failure 400 {object} models.ResponseError
+// @Failure 404 {object} models.ResponseError
+// @Failure 412 {object} models.ResponseError
+// @Failure 428 {object} models.ResponseError
+// @Failure 500 {object} models.ResponseError
 func (h *handlerV1) UpdateSpecPrice(c *gin.Context) {
 	var (
 		body        models.SpecPriceReq
@@ -129,7 +123,7 @@ func (h *handlerV1) UpdateSpecPrice(c *gin.Context) {
 
 	jspbMarshal.UseProtoNames = true
 	err := c.ShouldBindJSON(&body)
-	if handleBadRequestErrWithMessage(c, h.log, err, ErrorCodeInvalidJSON) {
+	if handleBindErr(c, h.log, err) {
 		return
 	}
 
@@ -153,26 +147,30 @@ func (h *handlerV1) UpdateSpecPrice(c *gin.Context) {
 			return
 		}
 		body.ID = int64(idToInt)
+		updateReq.Id = body.ID
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second*time.Duration(h.cfg.CtxTimeout))
+	ctx, cancel := context.WithTimeout(c.Request.Context(), time.Second*time.Duration(h.cfg.CtxTimeout))
 	defer cancel()
 
-	respSpecPrice, err := h.serviceManager.HealthCareService().UpdateSpecPrice(ctx, updateReq)
-	if handleInternalServerErrorWithMessage(c, h.log, err, ErrorCodeInternalServerError) {
+	if !h.checkIfMatch(c, func() (interface{}, error) {
+		price, err := h.serviceManager.HealthCareService().GetSpecPriceById(ctx, &pb.GetReqInt{Id: int64(updateReq.Id)})
+		if err != nil {
+			return nil, err
+		}
+		return viewerOf(c).specPrice(price), nil
+	}) {
 		return
 	}
 
-	response := models.SpecPriceModel{
-		ID:               respSpecPrice.Id,
-		DoctorId:         respSpecPrice.DoctorId,
-		SpecializationId: respSpecPrice.SpecializationId,
-		OnlinePrice:      respSpecPrice.OnlinePrice,
-		OfflinePrice:     respSpecPrice.OfflinePrice,
-		CreatedAt:        respSpecPrice.CreatedAt,
-		UpdatedAt:        respSpecPrice.UpdatedAt,
+	respSpecPrice, err := h.serviceManager.HealthCareService().UpdateSpecPrice(ctx, updateReq)
+	if handleGrpcErrWithMessage(c, h.log, err, "error while updating specialization price") {
+		return
 	}
 
+	response := viewerOf(c).specPrice(respSpecPrice)
+	auditChange(c, response.ID, response)
+
 	c.JSON(http.StatusOK, response)
 }
 
@@ -186,8 +184,9 @@ func (h *handlerV1) UpdateSpecPrice(c *gin.Context) {
 // @Produce json
 // @Param id path int64 true "id"
 // @Success 201 {object} models.Status
-// @Failure 400 string Error models.ResponseError
-// @Failure 500 string Error models.ResponseError
+// @Failure 400 {object} models.ResponseError
+// @Failure 404 {object} models.ResponseError
+// @Failure 500 {object} models.ResponseError
 func (h *handlerV1) DeleteSpecPrice(c *gin.Context) {
 	var jspbMarshal protojson.MarshalOptions
 	jspbMarshal.UseProtoNames = true
@@ -198,15 +197,15 @@ func (h *handlerV1) DeleteSpecPrice(c *gin.Context) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second*time.Duration(h.cfg.CtxTimeout))
+	ctx, cancel := context.WithTimeout(c.Request.Context(), time.Second*time.Duration(h.cfg.CtxTimeout))
 	defer cancel()
 
 	_, err = h.serviceManager.HealthCareService().DeleteSpecPrice(ctx, &pb.GetReqInt{Id: int64(idToInt)})
-	if handleInternalServerErrorWithMessage(c, h.log, err, "error while deleting specialization price") {
+	if handleGrpcErrWithMessage(c, h.log, err, "error while deleting specialization price") {
 		return
 	}
 
-	c.JSON(http.StatusOK, models.Status{Message: "specialization price was successfully deleted"})
+	c.JSON(http.StatusOK, models.Status{Message: translate(c, "specialization price was successfully deleted")})
 }
 
 // List specialization prices
@@ -220,8 +219,8 @@ func (h *handlerV1) DeleteSpecPrice(c *gin.Context) {
 // @Param page path string false "page"
 // @Param limit path string false "limit"
 // @Success 201 {object} models.ListSpecPrices
-// @Failure 400 string Error models.ResponseError
-// @Failure 500 string Error models.ResponseError
+// @Failure 400 {object} models.ResponseError
+// @Failure 500 {object} models.ResponseError
 func (h *handlerV1) ListSpecPrices(c *gin.Context) {
 	var jspbMarshal protojson.MarshalOptions
 	jspbMarshal.UseProtoNames = true
@@ -235,11 +234,11 @@ func (h *handlerV1) ListSpecPrices(c *gin.Context) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second*time.Duration(h.cfg.CtxTimeout))
+	ctx, cancel := context.WithTimeout(c.Request.Context(), time.Second*time.Duration(h.cfg.CtxTimeout))
 	defer cancel()
 
 	response, err := h.serviceManager.HealthCareService().GetAllSpecPrice(ctx, &pb.GetAll{Page: int64(page), Limit: int64(limit)})
-	if handleInternalServerErrorWithMessage(c, h.log, err, ErrorCodeInternalServerError) {
+	if handleGrpcErrWithMessage(c, h.log, err, "error while listing specialization prices") {
 		return
 	}
 
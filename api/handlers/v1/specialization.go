@@ -23,8 +23,8 @@ import (
 // @Produce json
 // @Param SpecializaionInfo body models.SpecializationReq true "Create specialization"
 // @Success 201 {object} models.SpecializationModel
-// @Failure 400 string Error models.ResponseError
-// @Failure 500 string Error models.ResponseError
+// @Failure 400 {object} models.ResponseError
+// @Failure 500 {object} models.ResponseError
 func (h *handlerV1) CreateSpecializaion(c *gin.Context) {
 	var (
 		body       models.SpecializationReq
@@ -33,7 +33,7 @@ func (h *handlerV1) CreateSpecializaion(c *gin.Context) {
 
 	jspMarshal.UseProtoNames = true
 	err := c.ShouldBindJSON(&body)
-	if handleBadRequestErrWithMessage(c, h.log, err, ErrorCodeInvalidJSON) {
+	if handleBindErr(c, h.log, err) {
 		return
 	}
 
@@ -43,22 +43,16 @@ func (h *handlerV1) CreateSpecializaion(c *gin.Context) {
 		DepartmentId: body.DepartmentId,
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second*time.Duration(h.cfg.CtxTimeout))
+	ctx, cancel := context.WithTimeout(c.Request.Context(), time.Second*time.Duration(h.cfg.CtxTimeout))
 	defer cancel()
 
 	respSpec, err := h.serviceManager.HealthCareService().CreateSpecialization(ctx, createReq)
-	if handleInternalServerErrorWithMessage(c, h.log, err, "error while creating a specialization") {
+	if handleGrpcErrWithMessage(c, h.log, err, "error while creating a specialization") {
 		return
 	}
 
-	response := models.SpecializationModel{
-		ID:           respSpec.Id,
-		Name:         respSpec.Name,
-		Description:  respSpec.Description,
-		DepartmentId: respSpec.DepartmentId,
-		CreatedAt:    respSpec.CreatedAt,
-		UpdatedAt:    respSpec.UpdatedAt,
-	}
+	response := viewerOf(c).specialization(respSpec)
+	auditChange(c, response.ID, response)
 
 	c.JSON(http.StatusOK, response)
 }
@@ -72,9 +66,11 @@ func (h *handlerV1) CreateSpecializaion(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param id path int64 true "id"
+// @Param If-None-Match header string false "ETag of the version the client has"
 // @Success 201 {object} models.SpecializationModel
-// @Failure 400 string Error models.ResponseError
-// @Failure 500 string Error models.ResponseError
+// @Failure 400 {object} models.ResponseError
+// @Failure 404 {object} models.ResponseError
+// @Failure 500 {object} models.ResponseError
 func (h *handlerV1) GetSpecializationById(c *gin.Context) {
 	var jspMarshal protojson.MarshalOptions
 	jspMarshal.UseProtoNames = true
@@ -85,24 +81,17 @@ func (h *handlerV1) GetSpecializationById(c *gin.Context) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second*time.Duration((h.cfg.CtxTimeout)))
+	ctx, cancel := context.WithTimeout(c.Request.Context(), time.Second*time.Duration((h.cfg.CtxTimeout)))
 	defer cancel()
 
 	respSpec, err := h.serviceManager.HealthCareService().GetSpecializationById(ctx, &pb.GetReqInt{Id: int64(idToInt)})
-	if handleInternalServerErrorWithMessage(c, h.log, err, "error while getting specialization by id") {
+	if handleGrpcErrWithMessage(c, h.log, err, "error while getting specialization by id") {
 		return
 	}
 
-	response := models.SpecializationModel{
-		ID:           respSpec.Id,
-		Name:         respSpec.Name,
-		Description:  respSpec.Description,
-		DepartmentId: respSpec.DepartmentId,
-		CreatedAt:    respSpec.CreatedAt,
-		UpdatedAt:    respSpec.UpdatedAt,
-	}
+	response := viewerOf(c).specialization(respSpec)
 
-	c.JSON(http.StatusOK, response)
+	writeWithETag(c, response)
 }
 
 // Update Specialization
@@ -115,9 +104,13 @@ func (h *handlerV1) GetSpecializationById(c *gin.Context) {
 // @Produce json
 // @Param id path int64 false "id"
 // @Param UserInfo body models.SpecializationReq true "Update specialization"
+// @Param If-Match header string false "ETag of the version being updated"
 // @Success 201 {object} models.SpecializationModel
-// @Failure 400 string Error models.ResponseError
-// @Failure 500 string Error models.ResponseError
+// @Failure 400 {object} models.ResponseError
+// @Failure 404 {object} models.ResponseError
+// @Failure 412 {object} models.ResponseError
+// @Failure 428 {object} models.ResponseError
+// @Failure 500 {object} models.ResponseError
 func (h *handlerV1) UpdateSpecialization(c *gin.Context) {
 	var (
 		body        models.SpecializationReq
@@ -126,7 +119,7 @@ func (h *handlerV1) UpdateSpecialization(c *gin.Context) {
 
 	jspbMarshal.UseProtoNames = true
 	err := c.ShouldBindJSON(&body)
-	if handleBadRequestErrWithMessage(c, h.log, err, ErrorCodeInvalidJSON) {
+	if handleBindErr(c, h.log, err) {
 		return
 	}
 
@@ -149,25 +142,30 @@ func (h *handlerV1) UpdateSpecialization(c *gin.Context) {
 			return
 		}
 		body.ID = int64(idToInt)
+		updateReq.Id = body.ID
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second*time.Duration(h.cfg.CtxTimeout))
+	ctx, cancel := context.WithTimeout(c.Request.Context(), time.Second*time.Duration(h.cfg.CtxTimeout))
 	defer cancel()
 
-	respSpec, err := h.serviceManager.HealthCareService().UpdateSpecialization(ctx, updateReq)
-	if handleInternalServerErrorWithMessage(c, h.log, err, ErrorCodeInternalServerError) {
+	if !h.checkIfMatch(c, func() (interface{}, error) {
+		spec, err := h.serviceManager.HealthCareService().GetSpecializationById(ctx, &pb.GetReqInt{Id: int64(updateReq.Id)})
+		if err != nil {
+			return nil, err
+		}
+		return viewerOf(c).specialization(spec), nil
+	}) {
 		return
 	}
 
-	response := models.SpecializationModel{
-		ID:           respSpec.Id,
-		Name:         respSpec.Name,
-		Description:  respSpec.Description,
-		DepartmentId: respSpec.DepartmentId,
-		CreatedAt:    respSpec.CreatedAt,
-		UpdatedAt:    respSpec.UpdatedAt,
+	respSpec, err := h.serviceManager.HealthCareService().UpdateSpecialization(ctx, updateReq)
+	if handleGrpcErrWithMessage(c, h.log, err, "error while updating specialization") {
+		return
 	}
 
+	response := viewerOf(c).specialization(respSpec)
+	auditChange(c, response.ID, response)
+
 	c.JSON(http.StatusOK, response)
 }
 
@@ -181,8 +179,9 @@ func (h *handlerV1) UpdateSpecialization(c *gin.Context) {
 // @Produce json
 // @Param id path int64 true "id"
 // @Success 201 {object} models.Status
-// @Failure 400 string Error models.ResponseError
-// @Failure 500 string Error models.ResponseError
+// @Failure 400 {object} models.ResponseError
+// @Failure 404 {object} models.ResponseError
+// @Failure 500 {object} models.ResponseError
 func (h *handlerV1) DeleteSpecialization(c *gin.Context) {
 	var jspbMarshal protojson.MarshalOptions
 	jspbMarshal.UseProtoNames = true
@@ -193,15 +192,15 @@ func (h *handlerV1) DeleteSpecialization(c *gin.Context) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second*time.Duration(h.cfg.CtxTimeout))
+	ctx, cancel := context.WithTimeout(c.Request.Context(), time.Second*time.Duration(h.cfg.CtxTimeout))
 	defer cancel()
 
 	_, err = h.serviceManager.HealthCareService().DeleteSpecialization(ctx, &pb.GetReqInt{Id: int64(idToInt)})
-	if handleInternalServerErrorWithMessage(c, h.log, err, "error while deleting specialization") {
+	if handleGrpcErrWithMessage(c, h.log, err, "error while deleting specialization") {
 		return
 	}
 
-	c.JSON(http.StatusOK, models.Status{Message: "specialization was successfully deleted"})
+	c.JSON(http.StatusOK, models.Status{Message: translate(c, "specialization was successfully deleted")})
 }
 
 // List specializations
@@ -215,8 +214,8 @@ func (h *handlerV1) DeleteSpecialization(c *gin.Context) {
 // @Param page path string false "page"
 // @Param limit path string false "limit"
 // @Success 201 {object} models.ListSpecializations
-// @Failure 400 string Error models.ResponseError
-// @Failure 500 string Error models.ResponseError
+// @Failure 400 {object} models.ResponseError
+// @Failure 500 {object} models.ResponseError
 func (h *handlerV1) ListSpecializations(c *gin.Context) {
 	var jspbMarshal protojson.MarshalOptions
 	jspbMarshal.UseProtoNames = true
@@ -230,11 +229,11 @@ func (h *handlerV1) ListSpecializations(c *gin.Context) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second*time.Duration(h.cfg.CtxTimeout))
+	ctx, cancel := context.WithTimeout(c.Request.Context(), time.Second*time.Duration(h.cfg.CtxTimeout))
 	defer cancel()
 
 	response, err := h.serviceManager.HealthCareService().GetAllSpecializations(ctx, &pb.GetAll{Page: int64(page), Limit: int64(limit)})
-	if handleInternalServerErrorWithMessage(c, h.log, err, ErrorCodeInternalServerError) {
+	if handleGrpcErrWithMessage(c, h.log, err, "error while listing specializations") {
 		return
 	}
 
@@ -253,8 +252,8 @@ func (h *handlerV1) ListSpecializations(c *gin.Context) {
 // @Param limit path string false "limit"
 // @Param department_id path int64 true "department_id"
 // @Success 201 {object} models.ListSpecializationsWithPrices
-// @Failure 400 string Error models.ResponseError
-// @Failure 500 string Error models.ResponseError
+// @Failure 400 {object} models.ResponseError
+// @Failure 500 {object} models.ResponseError
 func (h *handlerV1) ListSpecializationsByDepartmentId(c *gin.Context) {
 	var jspbMarshal protojson.MarshalOptions
 	jspbMarshal.UseProtoNames = true
@@ -275,11 +274,11 @@ func (h *handlerV1) ListSpecializationsByDepartmentId(c *gin.Context) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second*time.Duration(h.cfg.CtxTimeout))
+	ctx, cancel := context.WithTimeout(c.Request.Context(), time.Second*time.Duration(h.cfg.CtxTimeout))
 	defer cancel()
 
 	response, err := h.serviceManager.HealthCareService().GetAllSpecByDepartmentIdWithPrices(ctx, &pb.GetRequest{Page: int64(page), Limit: int64(limit), Id: int64(idToInt)})
-	if handleInternalServerErrorWithMessage(c, h.log, err, ErrorCodeInternalServerError) {
+	if handleGrpcErrWithMessage(c, h.log, err, "error while listing specializations of a department") {
 		return
 	}
 
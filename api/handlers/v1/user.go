@@ -3,12 +3,16 @@ package v1
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"myproject/admin-api-gateway/api/handlers/tokens"
 	"myproject/admin-api-gateway/api/models"
 	"myproject/admin-api-gateway/email"
 	pbu "myproject/admin-api-gateway/genproto/user-service"
 	"myproject/admin-api-gateway/pkg/etc"
+	"myproject/admin-api-gateway/pkg/featureflag"
+	"myproject/admin-api-gateway/pkg/i18n"
+	"myproject/admin-api-gateway/pkg/logger"
 	"net/http"
 	"strings"
 	"time"
@@ -29,9 +33,14 @@ import (
 // @Produce json
 // @Param UserData body models.User true "Register user"
 // @Success 201 {object} models.RegisterRespModel
-// @Failure 400 string error models.ResponseError
-// @Failure 500 string error models.ResponseError
+// @Failure 400 {object} models.ResponseError
+// @Failure 403 {object} models.ResponseError
+// @Failure 500 {object} models.ResponseError
 func (h *handlerV1) Register(c *gin.Context) {
+	if h.featureDisabled(c, featureflag.RegistrationClosed, "Registration is closed") {
+		return
+	}
+
 	var (
 		body       models.User
 		code       string
@@ -40,8 +49,8 @@ func (h *handlerV1) Register(c *gin.Context) {
 
 	jspMarshal.UseProtoNames = true
 
-	err := c.BindJSON(&body)
-	if handleBadRequestErrWithMessage(c, h.log, err, ErrorCodeInvalidJSON) {
+	err := c.ShouldBindJSON(&body)
+	if handleBindErr(c, h.log, err) {
 		return
 	}
 
@@ -50,11 +59,11 @@ func (h *handlerV1) Register(c *gin.Context) {
 	body.Email = strings.ToLower(body.Email)
 
 	err = body.Validate()
-	if handleBadRequestErrWithMessage(c, h.log, err, ErrorValidationError) {
+	if handleValidationErr(c, h.log, err) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second*time.Duration(h.cfg.CtxTimeout))
+	ctx, cancel := context.WithTimeout(c.Request.Context(), time.Second*time.Duration(h.cfg.CtxTimeout))
 	defer cancel()
 
 	exists, err := h.serviceManager.UserService().CheckField(ctx, &pbu.CheckFieldReq{
@@ -62,7 +71,7 @@ func (h *handlerV1) Register(c *gin.Context) {
 		Field: "email",
 	})
 
-	if handleInternalServerErrorWithMessage(c, h.log, err, "failed to check email uniqueness") {
+	if handleGrpcErrWithMessage(c, h.log, err, "failed to check email uniqueness") {
 		return
 	}
 
@@ -90,24 +99,26 @@ func (h *handlerV1) Register(c *gin.Context) {
 
 	timeOut := time.Second * 300
 
-	err = h.inMemoryStorage.SetWithTTL(registerUser.Email, string(userJson), int(timeOut.Seconds()))
+	err = h.inMemoryStorage.SetWithTTL(ctx, registerUser.Email, string(userJson), int(timeOut.Seconds()))
 	if handleInternalServerErrorWithMessage(c, h.log, err, "error while setting with ttl to redis") {
 		return
 	}
 
+	lang := i18n.FromContext(c.Request.Context())
 	message, err := email.SendVerificationCode(email.EmailPayload{
 		From:     h.cfg.SendEmailFrom,
 		To:       registerUser.Email,
 		Password: h.cfg.EmailCode,
 		Code:     registerUser.Code,
-		Message:  fmt.Sprintf("Hi, %s", registerUser.FirstName),
+		Message:  i18n.Tf(lang, "Hi, %s", registerUser.FirstName),
+		Language: lang,
 	})
 	if handleInternalServerErrorWithMessage(c, h.log, err, "error while sending code to user's email") {
 		return
 	}
 
 	c.JSON(http.StatusOK, models.RegisterRespModel{
-		Message: message,
+		Message: translate(c, message),
 	})
 }
 
@@ -121,8 +132,7 @@ func (h *handlerV1) Register(c *gin.Context) {
 // @Param email path string true "email"
 // @Param code path string true "code"
 // @Success 201 {object} models.VerifyRespModel
-// @Failure 400 string error models.ResponseError
-// @Failure 400 string error models.ResponseError
+// @Failure 400 {object} models.ResponseError
 func (h *handlerV1) Verify(c *gin.Context) {
 	var jspMarshal protojson.MarshalOptions
 	jspMarshal.UseProtoNames = true
@@ -130,13 +140,10 @@ func (h *handlerV1) Verify(c *gin.Context) {
 	userEmail := c.Param("email")
 	userCode := c.Param("code")
 
-	registeredUser, err := redis.Bytes(h.inMemoryStorage.Get(userEmail))
+	registeredUser, err := redis.Bytes(h.inMemoryStorage.Get(c.Request.Context(), userEmail))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.StandardErrorModel{
-			Status:  ErrorCodeNotFound,
-			Message: "Code is expired, try again",
-		})
-		h.log.Error("Code is expired, TTL is over.")
+		AbortWithError(c, http.StatusBadRequest, ErrorCodeNotFound, "Code is expired, try again")
+		logger.WithContext(h.log, c.Request.Context()).Error("Code is expired, TTL is over.")
 		return
 	}
 
@@ -163,7 +170,7 @@ func (h *handlerV1) Verify(c *gin.Context) {
 		Role:      "user",
 		SignInKey: h.cfg.SignInKey,
 		Log:       h.log,
-		TimeOut:   h.cfg.AccessTokenTimeOut,
+		TimeOut:   h.cfg.AccessTokenTimeout,
 	}
 
 	access, refresh, err := h.jwtHandler.GenerateAuthJWT()
@@ -171,7 +178,7 @@ func (h *handlerV1) Verify(c *gin.Context) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second*time.Duration(h.cfg.CtxTimeout))
+	ctx, cancel := context.WithTimeout(c.Request.Context(), time.Second*time.Duration(h.cfg.CtxTimeout))
 	defer cancel()
 
 	respUser, err := h.serviceManager.UserService().CreateUser(ctx, &pbu.User{
@@ -184,16 +191,11 @@ func (h *handlerV1) Verify(c *gin.Context) {
 		AccessToken:  access,
 		RefreshToken: refresh,
 	})
-	if handleInternalServerErrorWithMessage(c, h.log, err, "error while creating user") {
+	if handleGrpcErrWithMessage(c, h.log, err, "error while creating user") {
 		return
 	}
 	userModel := models.VerifyRespModel{
-		ID:          respUser.Id,
-		FirstName:   respUser.FirstName,
-		LastName:    respUser.LastName,
-		BirthDate:   respUser.BirthDate,
-		Email:       respUser.Email,
-		Password:    respUser.Password,
+		UserResp:    *viewerOf(c).user(respUser),
 		AccessToken: respUser.AccessToken,
 	}
 
@@ -208,8 +210,7 @@ func (h *handlerV1) Verify(c *gin.Context) {
 // @Produce json
 // @Param User body models.LoginReqModel true "Login"
 // @Success 201 {object} models.LoginRespModel
-// @Failure 400 string Error models.ResponseError
-// @Failure 400 string Error models.ResponseError
+// @Failure 400 {object} models.ResponseError
 // @Router /v1/login [post]
 func (h *handlerV1) Login(c *gin.Context) {
 	var (
@@ -218,19 +219,19 @@ func (h *handlerV1) Login(c *gin.Context) {
 	)
 
 	jspMarshal.UseProtoNames = true
-	err := c.ShouldBind(&body)
-	if handleBadRequestErrWithMessage(c, h.log, err, "error while marshaling user request body") {
+	err := c.ShouldBindJSON(&body)
+	if handleBindErr(c, h.log, err) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second*time.Duration(h.cfg.CtxTimeout))
+	ctx, cancel := context.WithTimeout(c.Request.Context(), time.Second*time.Duration(h.cfg.CtxTimeout))
 	defer cancel()
 
 	user, err := h.serviceManager.UserService().IfExists(ctx, &pbu.IfExistsReq{
 		Email: body.Email,
 	})
 
-	if handleInternalServerErrorWithMessage(c, h.log, err, "error while checking if user exists") {
+	if handleGrpcErrWithMessage(c, h.log, err, "error while checking if user exists") {
 		return
 	}
 
@@ -245,7 +246,7 @@ func (h *handlerV1) Login(c *gin.Context) {
 		Role:      "user",
 		SignInKey: h.cfg.SignInKey,
 		Log:       h.log,
-		TimeOut:   h.cfg.AccessTokenTimeOut,
+		TimeOut:   h.cfg.AccessTokenTimeout,
 	}
 
 	access, _, err := h.jwtHandler.GenerateAuthJWT()
@@ -254,12 +255,7 @@ func (h *handlerV1) Login(c *gin.Context) {
 	}
 
 	loginResp := models.UserModel{
-		ID:          user.User.Id,
-		FirstName:   user.User.FirstName,
-		LastName:    user.User.LastName,
-		BirthDate:   user.User.BirthDate,
-		Email:       user.User.Email,
-		Password:    user.User.Password,
+		UserResp:    *viewerOf(c).user(user.User),
 		AccessToken: access,
 	}
 
@@ -278,9 +274,12 @@ func (h *handlerV1) Login(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param UserInfo body models.User true "Create user"
+// @Param Idempotency-Key header string false "Unique key of the request, retries with the same key are replayed"
 // @Success 201 {object} models.UserModel
-// @Failure 400 string Error models.ResponseError
-// @Failure 500 string Error models.ResponseError
+// @Failure 400 {object} models.ResponseError
+// @Failure 409 {object} models.ResponseError
+// @Failure 422 {object} models.ResponseError
+// @Failure 500 {object} models.ResponseError
 func (h *handlerV1) CreateUser(c *gin.Context) {
 	var (
 		body       models.User
@@ -289,7 +288,7 @@ func (h *handlerV1) CreateUser(c *gin.Context) {
 
 	jspMarshal.UseProtoNames = true
 	err := c.ShouldBindJSON(&body)
-	if handleBadRequestErrWithMessage(c, h.log, err, ErrorCodeInvalidJSON) {
+	if handleBindErr(c, h.log, err) {
 		return
 	}
 
@@ -298,7 +297,7 @@ func (h *handlerV1) CreateUser(c *gin.Context) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second*time.Duration(h.cfg.CtxTimeout))
+	ctx, cancel := context.WithTimeout(c.Request.Context(), time.Second*time.Duration(h.cfg.CtxTimeout))
 	defer cancel()
 
 	body.ID = uuid.New().String()
@@ -307,7 +306,7 @@ func (h *handlerV1) CreateUser(c *gin.Context) {
 		Role:      "user",
 		SignInKey: h.cfg.SignInKey,
 		Log:       h.log,
-		TimeOut:   h.cfg.AccessTokenTimeOut,
+		TimeOut:   h.cfg.AccessTokenTimeout,
 	}
 	access, refresh, err := h.jwtHandler.GenerateAuthJWT()
 	if handleInternalServerErrorWithMessage(c, h.log, err, "error while generating access and refresh token") {
@@ -326,19 +325,15 @@ func (h *handlerV1) CreateUser(c *gin.Context) {
 	}
 
 	respUser, err := h.serviceManager.UserService().CreateUser(ctx, createReq)
-	if handleInternalServerErrorWithMessage(c, h.log, err, "error while creating a user") {
+	if handleGrpcErrWithMessage(c, h.log, err, "error while creating a user") {
 		return
 	}
 
 	response := models.UserModel{
-		ID:          respUser.Id,
-		FirstName:   respUser.FirstName,
-		LastName:    respUser.LastName,
-		BirthDate:   respUser.BirthDate,
-		Email:       respUser.Email,
-		Password:    respUser.Password,
+		UserResp:    *viewerOf(c).user(respUser),
 		AccessToken: respUser.AccessToken,
 	}
+	auditChange(c, response.ID, response)
 
 	c.JSON(http.StatusCreated, response)
 }
@@ -352,35 +347,30 @@ func (h *handlerV1) CreateUser(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param id path string true "id"
-// @Success 201 {object} models.User
-// @Failure 400 string Error models.ResponseError
-// @Failure 500 string Error models.ResponseError
+// @Param If-None-Match header string false "ETag of the version the client has"
+// @Success 201 {object} models.UserResp
+// @Failure 400 {object} models.ResponseError
+// @Failure 404 {object} models.ResponseError
+// @Failure 500 {object} models.ResponseError
 func (h *handlerV1) GetUserById(c *gin.Context) {
 	var jspMarshal protojson.MarshalOptions
 	jspMarshal.UseProtoNames = true
 
 	id := c.Param("id")
 
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second*time.Duration((h.cfg.CtxTimeout)))
+	ctx, cancel := context.WithTimeout(c.Request.Context(), time.Second*time.Duration((h.cfg.CtxTimeout)))
 	defer cancel()
 
 	respUser, err := h.serviceManager.UserService().GetUserById(ctx, &pbu.GetUserReqById{
 		UserId: id,
 	})
-	if handleInternalServerErrorWithMessage(c, h.log, err, "error while getting user by id") {
+	if handleGrpcErrWithMessage(c, h.log, err, "error while getting user by id") {
 		return
 	}
 
-	response := models.User{
-		ID:        respUser.Id,
-		FirstName: respUser.FirstName,
-		LastName:  respUser.LastName,
-		BirthDate: respUser.BirthDate,
-		Email:     respUser.Email,
-		Password:  respUser.Password,
-	}
+	response := viewerOf(c).user(respUser)
 
-	c.JSON(http.StatusOK, response)
+	writeWithETag(c, response)
 }
 
 // Update User
@@ -393,9 +383,13 @@ func (h *handlerV1) GetUserById(c *gin.Context) {
 // @Produce json
 // @Param id path string false "id"
 // @Param UserInfo body models.User true "Update User"
-// @Success 201 {object} models.User
-// @Failure 400 string Error models.ResponseError
-// @Failure 500 string Error models.ResponseError
+// @Param If-Match header string false "ETag of the version being updated"
+// @Success 201 {object} models.UserResp
+// @Failure 400 {object} models.ResponseError
+// @Failure 404 {object} models.ResponseError
+// @Failure 412 {object} models.ResponseError
+// @Failure 428 {object} models.ResponseError
+// @Failure 500 {object} models.ResponseError
 func (h *handlerV1) UpdateUser(c *gin.Context) {
 	var (
 		body        models.User
@@ -405,11 +399,11 @@ func (h *handlerV1) UpdateUser(c *gin.Context) {
 	jspbMarshal.UseProtoNames = true
 
 	err := c.ShouldBindJSON(&body)
-	if handleBadRequestErrWithMessage(c, h.log, err, ErrorCodeInvalidJSON) {
+	if handleBindErr(c, h.log, err) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second*time.Duration(h.cfg.CtxTimeout))
+	ctx, cancel := context.WithTimeout(c.Request.Context(), time.Second*time.Duration(h.cfg.CtxTimeout))
 	defer cancel()
 
 	id := c.Param("id")
@@ -426,13 +420,23 @@ func (h *handlerV1) UpdateUser(c *gin.Context) {
 	}
 
 	if updateReq.Id == "" {
-		if handleBadRequestErrWithMessage(c, h.log, fmt.Errorf("id is requir"), ErrorBadRequest) {
+		if handleBadRequestErrWithMessage(c, h.log, fmt.Errorf("id is required"), ErrorBadRequest) {
 			return
 		}
 	}
 
+	if !h.checkIfMatch(c, func() (interface{}, error) {
+		user, err := h.serviceManager.UserService().GetUserById(ctx, &pbu.GetUserReqById{UserId: updateReq.Id})
+		if err != nil {
+			return nil, err
+		}
+		return viewerOf(c).user(user), nil
+	}) {
+		return
+	}
+
 	respUser, err := h.serviceManager.UserService().UpdateUser(ctx, &pbu.User{
-		Id:        id,
+		Id:        updateReq.Id,
 		FirstName: body.FirstName,
 		LastName:  body.LastName,
 		BirthDate: body.BirthDate,
@@ -440,18 +444,12 @@ func (h *handlerV1) UpdateUser(c *gin.Context) {
 		Password:  body.Password,
 	})
 
-	if handleInternalServerErrorWithMessage(c, h.log, err, ErrorCodeInternalServerError) {
+	if handleGrpcErrWithMessage(c, h.log, err, "error while updating user") {
 		return
 	}
 
-	response := models.User{
-		ID:        respUser.Id,
-		FirstName: respUser.FirstName,
-		LastName:  respUser.LastName,
-		BirthDate: respUser.BirthDate,
-		Email:     respUser.Email,
-		Password:  respUser.Password,
-	}
+	response := viewerOf(c).user(respUser)
+	auditChange(c, response.ID, response)
 
 	c.JSON(http.StatusOK, response)
 }
@@ -466,26 +464,27 @@ func (h *handlerV1) UpdateUser(c *gin.Context) {
 // @Produce json
 // @Param id path string true "id"
 // @Success 201 {object} models.Status
-// @Failure 400 string Error models.ResponseError
-// @Failure 500 string Error models.ResponseError
+// @Failure 400 {object} models.ResponseError
+// @Failure 404 {object} models.ResponseError
+// @Failure 500 {object} models.ResponseError
 func (h *handlerV1) DeleteUser(c *gin.Context) {
 	var jspbMarshal protojson.MarshalOptions
 	jspbMarshal.UseProtoNames = true
 
 	id := c.Param("id")
 
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second*time.Duration(h.cfg.CtxTimeout))
+	ctx, cancel := context.WithTimeout(c.Request.Context(), time.Second*time.Duration(h.cfg.CtxTimeout))
 	defer cancel()
 
 	_, err := h.serviceManager.UserService().DeleteUser(ctx, &pbu.DeleteUserReq{
 		UserId: id,
 	})
 
-	if handleInternalServerErrorWithMessage(c, h.log, err, "error while deleting user") {
+	if handleGrpcErrWithMessage(c, h.log, err, "error while deleting user") {
 		return
 	}
 
-	c.JSON(http.StatusOK, models.Status{Message: "user was successfully deleted"})
+	c.JSON(http.StatusOK, models.Status{Message: translate(c, "user was successfully deleted")})
 }
 
 // List users
@@ -500,8 +499,8 @@ func (h *handlerV1) DeleteUser(c *gin.Context) {
 // @Param limit path string false "limit"
 // @Param filter path string false "filter"
 // @Success 201 {object} models.ListUsersResp
-// @Failure 400 string Error models.ResponseError
-// @Failure 500 string Error models.ResponseError
+// @Failure 400 {object} models.ResponseError
+// @Failure 500 {object} models.ResponseError
 func (h *handlerV1) ListUsers(c *gin.Context) {
 	var jspbMarshal protojson.MarshalOptions
 	jspbMarshal.UseProtoNames = true
@@ -516,18 +515,18 @@ func (h *handlerV1) ListUsers(c *gin.Context) {
 	}
 	filter := c.Param("filter")
 
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second*time.Duration(h.cfg.CtxTimeout))
+	ctx, cancel := context.WithTimeout(c.Request.Context(), time.Second*time.Duration(h.cfg.CtxTimeout))
 	defer cancel()
-	response, err := h.serviceManager.UserService().GetAllUsers(ctx, &pbu.ListUsersReq{
+	resp, err := h.serviceManager.UserService().GetAllUsers(ctx, &pbu.ListUsersReq{
 		Limit:  int64(limit),
 		Page:   int64(page),
 		Filter: filter,
 	})
-	if handleInternalServerErrorWithMessage(c, h.log, err, ErrorCodeInternalServerError) {
+	if handleGrpcErrWithMessage(c, h.log, err, "error while listing users") {
 		return
 	}
 
-	c.JSON(http.StatusOK, response)
+	c.JSON(http.StatusOK, models.ListUsersResp{Count: resp.Count, Users: viewerOf(c).users(resp.Users)})
 }
 
 // Change password
@@ -540,8 +539,7 @@ func (h *handlerV1) ListUsers(c *gin.Context) {
 // @Produce json
 // @Param Change-password body models.ChangePasswordReq true "Change password"
 // @Success 201 {object} models.Status
-// @Failure 400 string Error models.ResponseError
-// @Failure 400 string Error models.ResponseError
+// @Failure 400 {object} models.ResponseError
 func (h *handlerV1) ChangePassword(c *gin.Context) {
 	var (
 		body        models.ChangePasswordReq
@@ -551,12 +549,12 @@ func (h *handlerV1) ChangePassword(c *gin.Context) {
 	jspbMarshal.UseProtoNames = true
 
 	err := c.ShouldBindJSON(&body)
-	if handleBadRequestErrWithMessage(c, h.log, err, ErrorCodeInvalidJSON) {
+	if handleBindErr(c, h.log, err) {
 		return
 	}
 
 	err = body.Validate()
-	if handleBadRequestErrWithMessage(c, h.log, err, ErrorValidationError) {
+	if handleValidationErr(c, h.log, err) {
 		return
 	}
 
@@ -564,7 +562,7 @@ func (h *handlerV1) ChangePassword(c *gin.Context) {
 	if handleInternalServerErrorWithMessage(c, h.log, err, "error while hashing the password") {
 		return
 	}
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second*time.Duration(h.cfg.CtxTimeout))
+	ctx, cancel := context.WithTimeout(c.Request.Context(), time.Second*time.Duration(h.cfg.CtxTimeout))
 	defer cancel()
 
 	result, err := h.serviceManager.UserService().ChangePassword(ctx, &pbu.ChangeUserPasswordReq{
@@ -572,14 +570,14 @@ func (h *handlerV1) ChangePassword(c *gin.Context) {
 		Password: body.NewPassword,
 	})
 
-	if handleInternalServerErrorWithMessage(c, h.log, err, "error while changing password") {
+	if handleGrpcErrWithMessage(c, h.log, err, "error while changing password") {
 		return
 	}
 
 	if result.Status {
-		c.JSON(http.StatusOK, models.Status{Message: "password was successfully changed."})
+		c.JSON(http.StatusOK, models.Status{Message: translate(c, "password was successfully changed.")})
 	} else {
-		c.JSON(http.StatusOK, models.Status{Message: "failed to change the password"})
+		c.JSON(http.StatusOK, models.Status{Message: translate(c, "failed to change the password")})
 	}
 }
 
@@ -592,8 +590,7 @@ func (h *handlerV1) ChangePassword(c *gin.Context) {
 // @Produce json
 // @Param refresh-token body models.UpdateRefreshTokenReq true "Refresh token"
 // @Success 201 {object} models.UserModel
-// @Failure 400 string Error models.ResponseError
-// @Failure 400 string Error models.ResponseError
+// @Failure 400 {object} models.ResponseError
 func (h *handlerV1) UpdateRefreshToken(c *gin.Context) {
 	var (
 		body       models.UpdateRefreshTokenReq
@@ -602,7 +599,7 @@ func (h *handlerV1) UpdateRefreshToken(c *gin.Context) {
 
 	jspMarshal.UseProtoNames = true
 	err := c.ShouldBindJSON(&body)
-	if handleBadRequestErrWithMessage(c, h.log, err, ErrorCodeInvalidJSON) {
+	if handleBindErr(c, h.log, err) {
 		return
 	}
 
@@ -612,7 +609,7 @@ func (h *handlerV1) UpdateRefreshToken(c *gin.Context) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second*time.Duration(h.cfg.CtxTimeout))
+	ctx, cancel := context.WithTimeout(c.Request.Context(), time.Second*time.Duration(h.cfg.CtxTimeout))
 	defer cancel()
 
 	userId := cast.ToString(claims["sub"])
@@ -621,7 +618,7 @@ func (h *handlerV1) UpdateRefreshToken(c *gin.Context) {
 		Value: userId,
 	})
 
-	if handleInternalServerErrorWithMessage(c, h.log, err, "error while checking if user exists with this refresh token") {
+	if handleGrpcErrWithMessage(c, h.log, err, "error while checking if user exists with this refresh token") {
 		return
 	}
 
@@ -636,7 +633,7 @@ func (h *handlerV1) UpdateRefreshToken(c *gin.Context) {
 		Role:      "user",
 		SignInKey: h.cfg.SignInKey,
 		Log:       h.log,
-		TimeOut:   h.cfg.AccessTokenTimeOut,
+		TimeOut:   h.cfg.AccessTokenTimeout,
 	}
 
 	access, refresh, err := h.jwtHandler.GenerateAuthJWT()
@@ -648,28 +645,23 @@ func (h *handlerV1) UpdateRefreshToken(c *gin.Context) {
 		UserId:       userId,
 		RefreshToken: refresh,
 	})
-	if handleInternalServerErrorWithMessage(c, h.log, err, "error while updating refresh token") {
+	if handleGrpcErrWithMessage(c, h.log, err, "error while updating refresh token") {
 		return
 	}
 
 	if !status.Status {
-		if handleInternalServerErrorWithMessage(c, h.log, err, "error while updating refresh token, status false") {
+		if handleInternalServerErrorWithMessage(c, h.log, errors.New("refresh token was not updated"), "error while updating refresh token, status false") {
 			return
 		}
 	}
 
 	user, err := h.serviceManager.UserService().GetUserById(ctx, &pbu.GetUserReqById{UserId: userId})
-	if handleInternalServerErrorWithMessage(c, h.log, err, ErrorCodeNotFound) {
+	if handleGrpcErrWithMessage(c, h.log, err, ErrorCodeNotFound) {
 		return
 	}
 
 	response := models.UserModel{
-		ID:          user.Id,
-		FirstName:   user.FirstName,
-		LastName:    user.LastName,
-		BirthDate:   user.BirthDate,
-		Email:       user.Email,
-		Password:    user.Password,
+		UserResp:    *viewerOf(c).user(user),
 		AccessToken: access,
 	}
 
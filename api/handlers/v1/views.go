@@ -0,0 +1,146 @@
+package v1
+
+import (
+	"myproject/admin-api-gateway/api/handlers/tokens"
+	"myproject/admin-api-gateway/api/models"
+	pb "myproject/admin-api-gateway/genproto/healthcare-service"
+	pbu "myproject/admin-api-gateway/genproto/user-service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Backend objects are never written to clients as they are: handlers turn
+// them into the public models with the views below. A view copies the
+// fields it lists and nothing else, so a field added to a proto or a table
+// stays inside the gateway until it is added here. Password hashes and
+// refresh tokens are never copied, salaries only for admins. ETags are
+// computed over the views too, so they only change with what the caller sees.
+
+// viewer is the caller a response is shaped for
+type viewer struct {
+	role string
+}
+
+// viewerOf returns the caller of the request, the Auth middleware stores
+// its role in the context. Unauthenticated callers get the narrowest views.
+func viewerOf(c *gin.Context) viewer {
+	return viewer{role: c.GetString(tokens.RoleKey)}
+}
+
+func (v viewer) isAdmin() bool {
+	return v.role == "admin" || v.role == "superadmin"
+}
+
+func (v viewer) doctor(doctor *pb.Doctor) *models.DoctorResp {
+	view := &models.DoctorResp{
+		ID:            doctor.Id,
+		FullName:      doctor.FullName,
+		BirthDate:     doctor.BirthDate,
+		Gender:        doctor.Gender,
+		PhoneNumber:   doctor.PhoneNumber,
+		Email:         doctor.Email,
+		Address:       doctor.Address,
+		Biography:     doctor.Biography,
+		StartWorkYear: doctor.StartWorkYear,
+		EndWorkYear:   doctor.EndWorkYear,
+		WorkYears:     doctor.WorkYears,
+		DepartmentId:  doctor.DepartmentId,
+		SpecIds:       doctor.SpecIds,
+		IsVerified:    doctor.IsVerified,
+		CreatedAt:     doctor.CreatedAt,
+		UpdatedAt:     doctor.UpdatedAt,
+	}
+	if v.isAdmin() {
+		salary := float64(doctor.Salary)
+		view.Salary = &salary
+	}
+
+	return view
+}
+
+func (v viewer) doctors(doctors []*pb.Doctor) []*models.DoctorResp {
+	views := make([]*models.DoctorResp, 0, len(doctors))
+	for _, doctor := range doctors {
+		views = append(views, v.doctor(doctor))
+	}
+
+	return views
+}
+
+func (v viewer) user(user *pbu.User) *models.UserResp {
+	return &models.UserResp{
+		ID:        user.Id,
+		FirstName: user.FirstName,
+		LastName:  user.LastName,
+		BirthDate: user.BirthDate,
+		Email:     user.Email,
+		CreatedAt: user.CreatedAt,
+		UpdatedAt: user.UpdatedAt,
+	}
+}
+
+func (v viewer) users(users []*pbu.User) []*models.UserResp {
+	views := make([]*models.UserResp, 0, len(users))
+	for _, user := range users {
+		views = append(views, v.user(user))
+	}
+
+	return views
+}
+
+func (v viewer) admin(admin *models.AdminReq) *models.Admin {
+	return &models.Admin{
+		Id:        admin.Id,
+		FullName:  admin.FullName,
+		Age:       admin.Age,
+		Email:     admin.Email,
+		UserName:  admin.UserName,
+		Role:      admin.Role,
+		CreatedAt: admin.CreatedAt,
+	}
+}
+
+func (v viewer) admins(admins []*models.AdminReq) []*models.Admin {
+	views := make([]*models.Admin, 0, len(admins))
+	for _, admin := range admins {
+		views = append(views, v.admin(admin))
+	}
+
+	return views
+}
+
+func (v viewer) department(department *pb.Department) *models.DepartmentResp {
+	return &models.DepartmentResp{
+		ID:          department.Id,
+		Name:        department.Name,
+		Description: department.Description,
+		ComeTime:    department.ComeTime,
+		FinishTime:  department.FinishTime,
+		ImageUrl:    department.ImageUrl,
+		CreatedAt:   department.CreatedAt,
+		UpdatedAt:   department.UpdatedAt,
+	}
+}
+
+func (v viewer) specialization(spec *pb.Specializations) *models.SpecializationModel {
+	return &models.SpecializationModel{
+		ID:           spec.Id,
+		Name:         spec.Name,
+		Description:  spec.Description,
+		DepartmentId: spec.DepartmentId,
+		CreatedAt:    spec.CreatedAt,
+		UpdatedAt:    spec.UpdatedAt,
+	}
+}
+
+func (v viewer) specPrice(price *pb.DocSpecPrices) *models.SpecPriceModel {
+	return &models.SpecPriceModel{
+		ID:               price.Id,
+		DoctorId:         price.DoctorId,
+		SpecializationId: price.SpecializationId,
+		OnlinePrice:      price.OnlinePrice,
+		OfflinePrice:     price.OfflinePrice,
+		CreatedAt:        price.CreatedAt,
+		UpdatedAt:        price.UpdatedAt,
+	}
+}
@@ -0,0 +1,129 @@
+package middleware
+
+import (
+	"bytes"
+	"io"
+	"math/rand"
+	"myproject/admin-api-gateway/config"
+	"myproject/admin-api-gateway/pkg/logger"
+	"myproject/admin-api-gateway/pkg/redact"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const maxLoggedBody = 4096
+
+// accessLogSettings is replaced as a whole when the config is reloaded
+type accessLogSettings struct {
+	sampleRate    float64
+	slowThreshold time.Duration
+	body          bool
+}
+
+// AccessLog writes one structured line per request through pkg/logger.
+// Server errors, client errors and slow requests are always logged,
+// other requests are sampled by cfg.AccessLogSampleRate.
+func AccessLog(log logger.Logger, watcher *config.Watcher) gin.HandlerFunc {
+	cfg := watcher.Current()
+
+	var settings atomic.Pointer[accessLogSettings]
+	load := func(cfg config.Config) *accessLogSettings {
+		return &accessLogSettings{
+			sampleRate:    cfg.AccessLogSampleRate,
+			slowThreshold: time.Duration(cfg.AccessLogSlowThreshold) * time.Millisecond,
+			body:          cfg.AccessLogBody,
+		}
+	}
+	settings.Store(load(cfg))
+	watcher.Subscribe(func(_, next config.Config) {
+		settings.Store(load(next))
+	})
+
+	return func(ctx *gin.Context) {
+		start := time.Now()
+		current := settings.Load()
+
+		var body string
+		if current.body {
+			body = readBody(ctx.Request)
+		}
+
+		ctx.Next()
+
+		latency := time.Since(start)
+		status := ctx.Writer.Status()
+		slow := current.slowThreshold > 0 && latency >= current.slowThreshold
+
+		if status < http.StatusBadRequest && !slow && rand.Float64() >= current.sampleRate {
+			return
+		}
+
+		route := ctx.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+
+		subject, role := identify(ctx, cfg.SignInKey)
+		fields := []logger.Field{
+			logger.String("method", ctx.Request.Method),
+			logger.String("route", route),
+			logger.String("query", redact.Query(ctx.Request.URL.Query())),
+			logger.Int("status", status),
+			logger.Duration("latency", latency),
+			logger.Int("bytes", ctx.Writer.Size()),
+			logger.String("client_ip", ctx.ClientIP()),
+			logger.String("user_agent", ctx.Request.UserAgent()),
+			logger.String("sub", subject),
+			logger.String("role", role),
+		}
+		if body != "" {
+			fields = append(fields, logger.String("body", body))
+		}
+		if len(ctx.Errors) > 0 {
+			fields = append(fields, logger.String("errors", redact.String(ctx.Errors.String())))
+		}
+
+		log := logger.WithContext(log, ctx.Request.Context())
+		switch {
+		case status >= http.StatusInternalServerError:
+			log.Error("request failed", fields...)
+		case slow:
+			log.Warn("slow request", fields...)
+		default:
+			log.Info("request", fields...)
+		}
+	}
+}
+
+// readBody returns the redacted JSON body of the request and restores it for the handlers
+func readBody(req *http.Request) string {
+	if req.Body == nil || req.ContentLength == 0 || !strings.Contains(req.Header.Get("Content-Type"), "json") {
+		return ""
+	}
+
+	raw, err := io.ReadAll(io.LimitReader(req.Body, maxLoggedBody+1))
+	req.Body = readCloser{Reader: io.MultiReader(bytes.NewReader(raw), req.Body), Closer: req.Body}
+	if err != nil || len(raw) == 0 {
+		return ""
+	}
+
+	if len(raw) > maxLoggedBody {
+		return "[TRUNCATED]"
+	}
+
+	body, ok := redact.JSON(raw)
+	if !ok {
+		return "[NON-JSON]"
+	}
+
+	return body
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
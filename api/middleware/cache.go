@@ -0,0 +1,281 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"myproject/admin-api-gateway/config"
+	"myproject/admin-api-gateway/pkg/etag"
+	"myproject/admin-api-gateway/pkg/logger"
+	"myproject/admin-api-gateway/storage/repo"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/cast"
+	"golang.org/x/sync/singleflight"
+)
+
+type cachedResponse struct {
+	Status      int    `json:"status"`
+	ContentType string `json:"content_type"`
+	ETag        string `json:"etag,omitempty"`
+	Body        []byte `json:"body"`
+}
+
+// ResponseCache keeps successful GET responses in redis. Every domain has a
+// generation counter that is part of the key, bumping it on writes makes all
+// cached entries of the domain unreachable until they expire.
+type ResponseCache struct {
+	store    repo.InMemoryStorageI
+	log      logger.Logger
+	settings atomic.Pointer[cacheSettings]
+	group    singleflight.Group
+}
+
+// cacheSettings is replaced as a whole when the config is reloaded
+type cacheSettings struct {
+	enabled    bool
+	defaultTTL time.Duration
+	routeTTLs  map[string]time.Duration
+	signInKey  string
+}
+
+func NewResponseCache(store repo.InMemoryStorageI, watcher *config.Watcher, log logger.Logger) *ResponseCache {
+	cache := &ResponseCache{
+		store: store,
+		log:   log,
+	}
+	cache.settings.Store(cache.loadSettings(watcher.Current()))
+	watcher.Subscribe(func(_, next config.Config) {
+		cache.settings.Store(cache.loadSettings(next))
+	})
+
+	return cache
+}
+
+func (r *ResponseCache) loadSettings(cfg config.Config) *cacheSettings {
+	settings := &cacheSettings{
+		enabled:    cfg.CacheEnabled,
+		defaultTTL: time.Duration(cfg.CacheTTL) * time.Second,
+		routeTTLs:  make(map[string]time.Duration),
+		signInKey:  cfg.SignInKey,
+	}
+
+	for _, item := range splitRules(cfg.CacheRouteTTLs) {
+		route, value, _ := strings.Cut(item, "=")
+		ttl, err := time.ParseDuration(strings.TrimSpace(value))
+		if err != nil || ttl <= 0 {
+			r.log.Error("invalid cache ttl, expected 'METHOD /path=duration'", logger.String("rule", item))
+			continue
+		}
+		settings.routeTTLs[strings.TrimSpace(route)] = ttl
+	}
+
+	return settings
+}
+
+// Cached serves the route from the cache of the given domain. Concurrent
+// misses of the same key are coalesced so that only one reaches the backend.
+func (r *ResponseCache) Cached(domain string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		settings := r.settings.Load()
+		if !settings.enabled || ctx.Request.Method != http.MethodGet {
+			return
+		}
+
+		ttl := settings.ttl(ctx.Request.Method + " " + ctx.FullPath())
+		ctx.Writer = &cacheControlWriter{ResponseWriter: ctx.Writer, maxAge: int(ttl.Seconds())}
+
+		_, role := identify(ctx, settings.signInKey)
+		key, err := r.key(ctx.Request.Context(), domain, role, ctx.Request)
+		if err != nil {
+			logger.WithContext(r.log, ctx.Request.Context()).Warn("response cache is unavailable", logger.Error(err))
+			return
+		}
+
+		if cached, ok := r.get(ctx.Request.Context(), key); ok {
+			ctx.Header("X-Cache", "HIT")
+			writeCached(ctx, cached)
+			return
+		}
+
+		var leader bool
+		value, _, _ := r.group.Do(key, func() (interface{}, error) {
+			leader = true
+			ctx.Header("X-Cache", "MISS")
+
+			recorder := &bodyRecorder{ResponseWriter: ctx.Writer}
+			ctx.Writer = recorder
+			ctx.Next()
+
+			if recorder.Status() != http.StatusOK {
+				return nil, nil
+			}
+			cached := &cachedResponse{
+				Status:      recorder.Status(),
+				ContentType: recorder.Header().Get("Content-Type"),
+				ETag:        recorder.Header().Get("ETag"),
+				Body:        recorder.body.Bytes(),
+			}
+			r.set(context.WithoutCancel(ctx.Request.Context()), key, cached, ttl)
+
+			return cached, nil
+		})
+		if leader {
+			return
+		}
+
+		// the leader's response was not cacheable, ask the backend ourselves
+		cached, _ := value.(*cachedResponse)
+		if cached == nil {
+			ctx.Header("X-Cache", "MISS")
+			ctx.Next()
+			return
+		}
+
+		ctx.Header("X-Cache", "HIT")
+		writeCached(ctx, cached)
+	}
+}
+
+// Invalidate drops the cached entries of the domains once the route
+// handler responded with success
+func (r *ResponseCache) Invalidate(domains ...string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		ctx.Next()
+
+		if !r.settings.Load().enabled || ctx.Writer.Status() >= http.StatusBadRequest {
+			return
+		}
+
+		for _, domain := range domains {
+			if _, err := r.store.Incr(context.WithoutCancel(ctx.Request.Context()), generationKey(domain)); err != nil {
+				logger.WithContext(r.log, ctx.Request.Context()).Error("cannot invalidate response cache",
+					logger.String("domain", domain), logger.Error(err))
+			}
+		}
+	}
+}
+
+func (r *cacheSettings) ttl(route string) time.Duration {
+	if ttl, ok := r.routeTTLs[route]; ok {
+		return ttl
+	}
+
+	return r.defaultTTL
+}
+
+// key is built from the domain generation, the role of the caller, the
+// path with its parameters and the sorted query, so pages, filters and the
+// views of every role are cached separately
+func (r *ResponseCache) key(ctx context.Context, domain, role string, req *http.Request) (string, error) {
+	generation, err := r.store.Get(ctx, generationKey(domain))
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256([]byte(role + " " + req.URL.Path + "?" + req.URL.Query().Encode()))
+	return fmt.Sprintf("cache:%s:%d:%s", domain, cast.ToInt64(cast.ToString(generation)), hex.EncodeToString(sum[:])), nil
+}
+
+func (r *ResponseCache) get(ctx context.Context, key string) (*cachedResponse, bool) {
+	value, err := r.store.Get(ctx, key)
+	if err != nil || value == nil {
+		return nil, false
+	}
+
+	var cached cachedResponse
+	if err := json.Unmarshal([]byte(cast.ToString(value)), &cached); err != nil {
+		return nil, false
+	}
+
+	return &cached, true
+}
+
+func (r *ResponseCache) set(ctx context.Context, key string, cached *cachedResponse, ttl time.Duration) {
+	value, err := json.Marshal(cached)
+	if err == nil {
+		err = r.store.SetWithTTL(ctx, key, string(value), int(ttl.Seconds()))
+	}
+	if err != nil {
+		logger.WithContext(r.log, ctx).Warn("cannot store response in cache", logger.Error(err))
+	}
+}
+
+func generationKey(domain string) string {
+	return "cache:generation:" + domain
+}
+
+func writeCached(ctx *gin.Context, cached *cachedResponse) {
+	if cached.ETag != "" {
+		ctx.Header("ETag", cached.ETag)
+		if etag.Match(ctx.GetHeader("If-None-Match"), cached.ETag) {
+			ctx.AbortWithStatus(http.StatusNotModified)
+			return
+		}
+	}
+
+	ctx.Header("Content-Length", strconv.Itoa(len(cached.Body)))
+	ctx.Data(cached.Status, cached.ContentType, cached.Body)
+	ctx.Abort()
+}
+
+// cacheControlWriter lets browsers keep successful responses for maxAge and
+// tells them not to store anything else, so errors are not kept for the TTL.
+// The header is chosen once the status is known, right before it is sent.
+type cacheControlWriter struct {
+	gin.ResponseWriter
+	maxAge int
+}
+
+func (w *cacheControlWriter) WriteHeaderNow() {
+	w.setCacheControl()
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+func (w *cacheControlWriter) Write(data []byte) (int, error) {
+	w.setCacheControl()
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *cacheControlWriter) WriteString(data string) (int, error) {
+	w.setCacheControl()
+	return w.ResponseWriter.WriteString(data)
+}
+
+func (w *cacheControlWriter) setCacheControl() {
+	if w.Written() {
+		return
+	}
+
+	// a 304 revalidates the stored 200, so it keeps the same lifetime
+	switch w.Status() {
+	case http.StatusOK, http.StatusNotModified:
+		w.Header().Set("Cache-Control", fmt.Sprintf("private, max-age=%d", w.maxAge))
+	default:
+		w.Header().Set("Cache-Control", "no-store")
+	}
+}
+
+// bodyRecorder copies the response body while it is written to the client
+type bodyRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *bodyRecorder) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *bodyRecorder) WriteString(data string) (int, error) {
+	w.body.WriteString(data)
+	return w.ResponseWriter.WriteString(data)
+}
@@ -0,0 +1,98 @@
+package middleware
+
+import (
+	"myproject/admin-api-gateway/config"
+	"myproject/admin-api-gateway/pkg/logger"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CORS answers preflight requests and adds the CORS headers for allowed
+// origins. It runs before rate limiting and casbin so that preflights,
+// which carry no credentials, are never rejected.
+func CORS(cfg config.Config, log logger.Logger) gin.HandlerFunc {
+	var (
+		anyOrigin bool
+		origins   = make(map[string]bool)
+		suffixes  []string
+	)
+	for _, origin := range splitRules(cfg.CORSAllowedOrigins) {
+		switch {
+		case origin == "*":
+			anyOrigin = true
+		case strings.Contains(origin, "://*."):
+			scheme, host, _ := strings.Cut(origin, "://*")
+			suffixes = append(suffixes, scheme+"://|"+strings.ToLower(host))
+		default:
+			origins[strings.ToLower(strings.TrimSuffix(origin, "/"))] = true
+		}
+	}
+
+	allowCredentials := cfg.CORSAllowCredentials
+	if anyOrigin && allowCredentials {
+		log.Warn("CORS credentials are not allowed together with the * origin, disabling credentials")
+		allowCredentials = false
+	}
+
+	methods := strings.Join(splitRules(cfg.CORSAllowedMethods), ", ")
+	headers := strings.Join(splitRules(cfg.CORSAllowedHeaders), ", ")
+	exposed := strings.Join(splitRules(cfg.CORSExposedHeaders), ", ")
+	maxAge := strconv.Itoa(cfg.CORSMaxAge)
+
+	allowed := func(origin string) bool {
+		origin = strings.ToLower(origin)
+		if anyOrigin || origins[origin] {
+			return true
+		}
+		for _, suffix := range suffixes {
+			scheme, host, _ := strings.Cut(suffix, "|")
+			if strings.HasPrefix(origin, scheme) && strings.HasSuffix(origin, host) {
+				return true
+			}
+		}
+		return false
+	}
+
+	return func(ctx *gin.Context) {
+		origin := ctx.GetHeader("Origin")
+		if origin == "" {
+			return
+		}
+
+		ctx.Writer.Header().Add("Vary", "Origin")
+		preflight := ctx.Request.Method == http.MethodOptions && ctx.GetHeader("Access-Control-Request-Method") != ""
+
+		if !allowed(origin) {
+			if preflight {
+				ctx.AbortWithStatus(http.StatusForbidden)
+			}
+			return
+		}
+
+		if anyOrigin {
+			ctx.Header("Access-Control-Allow-Origin", "*")
+		} else {
+			ctx.Header("Access-Control-Allow-Origin", origin)
+		}
+		if allowCredentials {
+			ctx.Header("Access-Control-Allow-Credentials", "true")
+		}
+
+		if !preflight {
+			if exposed != "" {
+				ctx.Header("Access-Control-Expose-Headers", exposed)
+			}
+			return
+		}
+
+		ctx.Writer.Header().Add("Vary", "Access-Control-Request-Method")
+		ctx.Writer.Header().Add("Vary", "Access-Control-Request-Headers")
+		ctx.Header("Access-Control-Allow-Methods", methods)
+		ctx.Header("Access-Control-Allow-Headers", headers)
+		ctx.Header("Access-Control-Max-Age", maxAge)
+		ctx.AbortWithStatus(http.StatusNoContent)
+	}
+}
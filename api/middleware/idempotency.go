@@ -0,0 +1,132 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	v1 "myproject/admin-api-gateway/api/handlers/v1"
+	"myproject/admin-api-gateway/config"
+	"myproject/admin-api-gateway/pkg/logger"
+	"myproject/admin-api-gateway/pkg/requestid"
+	"myproject/admin-api-gateway/storage/repo"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/spf13/cast"
+)
+
+const (
+	idempotencyHeader    = "Idempotency-Key"
+	maxIdempotencyKey    = 255
+	maxIdempotentBody    = 1 << 20
+	idempotencyKeyPrefix = "idempotency:"
+)
+
+type idempotentResponse struct {
+	Fingerprint string `json:"fingerprint"`
+	cachedResponse
+}
+
+// Idempotency replays the stored response when a request is retried with the
+// same Idempotency-Key and body. Keys are scoped to the caller and the route,
+// reusing a key with another body is rejected with 422 and a retry that
+// arrives while the first request is in flight gets 409.
+func Idempotency(store repo.InMemoryStorageI, cfg config.Config, log logger.Logger) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		key := ctx.GetHeader(idempotencyHeader)
+		if key == "" {
+			return
+		}
+		if len(key) > maxIdempotencyKey {
+			v1.AbortWithError(ctx, http.StatusBadRequest, v1.ErrorBadRequest, "Idempotency-Key should not be longer than 255 characters")
+			return
+		}
+
+		// one byte more than the limit tells a body at the limit from a larger one
+		body, err := io.ReadAll(io.LimitReader(ctx.Request.Body, maxIdempotentBody+1))
+		if err != nil {
+			v1.AbortWithError(ctx, http.StatusBadRequest, v1.ErrorCodeInvalidJSON, "Cannot read request body")
+			return
+		}
+		if len(body) > maxIdempotentBody {
+			v1.AbortWithError(ctx, http.StatusRequestEntityTooLarge, v1.ErrorCodePayloadTooLarge, "Request bodies sent with an Idempotency-Key should not be larger than 1 MiB")
+			return
+		}
+		ctx.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		subject, _ := identify(ctx, cfg.SignInKey)
+		if subject == "" {
+			subject = ctx.ClientIP()
+		}
+		scope := sha256.Sum256([]byte(subject + "\n" + ctx.FullPath() + "\n" + key))
+		recordKey := idempotencyKeyPrefix + hex.EncodeToString(scope[:])
+		fingerprint := sha256.Sum256(append([]byte(ctx.Request.Method+" "+ctx.Request.URL.Path+"\n"), body...))
+
+		reqCtx := ctx.Request.Context()
+		log := logger.WithContext(log, reqCtx)
+
+		owner := requestid.FromContext(reqCtx)
+		if owner == "" {
+			owner = uuid.NewString()
+		}
+		locked, err := store.SetNX(reqCtx, recordKey+":lock", owner, cfg.IdempotencyLockTTL)
+		if err != nil {
+			log.Warn("idempotency store is unavailable, request is processed without it", logger.Error(err))
+			return
+		}
+		if !locked {
+			v1.AbortWithError(ctx, http.StatusConflict, v1.ErrorCodeIdempotencyInProgress, "A request with this Idempotency-Key is still being processed")
+			return
+		}
+		defer func() {
+			// a request outliving the lock TTL must not release the lock of a retry
+			if _, err := store.DelIfEquals(context.WithoutCancel(reqCtx), recordKey+":lock", owner); err != nil {
+				log.Error("cannot release idempotency lock", logger.Error(err))
+			}
+		}()
+
+		// the record is read under the lock so a retry never races the first request
+		if stored, err := store.Get(reqCtx, recordKey); err == nil && stored != nil {
+			var record idempotentResponse
+			if err := json.Unmarshal([]byte(cast.ToString(stored)), &record); err == nil {
+				if record.Fingerprint != hex.EncodeToString(fingerprint[:]) {
+					v1.AbortWithError(ctx, http.StatusUnprocessableEntity, v1.ErrorCodeIdempotencyMismatch, "Idempotency-Key was already used with a different request body")
+					return
+				}
+
+				ctx.Header("Idempotent-Replayed", "true")
+				ctx.Data(record.Status, record.ContentType, record.Body)
+				ctx.Abort()
+				return
+			}
+		}
+
+		recorder := &bodyRecorder{ResponseWriter: ctx.Writer}
+		ctx.Writer = recorder
+		ctx.Next()
+
+		// server errors are not stored so the client can retry them
+		if recorder.Status() >= http.StatusInternalServerError {
+			return
+		}
+
+		record, err := json.Marshal(idempotentResponse{
+			Fingerprint: hex.EncodeToString(fingerprint[:]),
+			cachedResponse: cachedResponse{
+				Status:      recorder.Status(),
+				ContentType: recorder.Header().Get("Content-Type"),
+				Body:        recorder.body.Bytes(),
+			},
+		})
+		if err == nil {
+			err = store.SetWithTTL(context.WithoutCancel(reqCtx), recordKey, string(record), cfg.IdempotencyTTL)
+		}
+		if err != nil {
+			log.Error("cannot store idempotent response", logger.Error(err))
+		}
+	}
+}
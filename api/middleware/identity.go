@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"myproject/admin-api-gateway/api/handlers/tokens"
+
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/cast"
+)
+
+// identify parses the bearer token of the request once and caches its subject
+// and role in the gin context. Requests without a valid token are "unauthorized".
+func identify(ctx *gin.Context, signInKey string) (string, string) {
+	if role, ok := ctx.Get(tokens.RoleKey); ok {
+		return ctx.GetString(tokens.SubjectKey), cast.ToString(role)
+	}
+
+	subject, role := "", "unauthorized"
+	if token := bearerToken(ctx.Request); token != "" {
+		claims, err := tokens.ExtractClaims(token, []byte(signInKey))
+		if err == nil {
+			subject = cast.ToString(claims["sub"])
+			role = cast.ToString(claims["role"])
+		}
+	}
+
+	ctx.Set(tokens.SubjectKey, subject)
+	ctx.Set(tokens.RoleKey, role)
+	return subject, role
+}
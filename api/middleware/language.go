@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"myproject/admin-api-gateway/config"
+	"myproject/admin-api-gateway/pkg/i18n"
+	"myproject/admin-api-gateway/pkg/logger"
+	"myproject/admin-api-gateway/storage/repo"
+
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/cast"
+)
+
+// Language picks the language of the response: the language saved by the
+// caller takes precedence over the Accept-Language header, English is
+// used when neither names a supported language.
+func Language(store repo.InMemoryStorageI, cfg config.Config, log logger.Logger) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		lang := ""
+		if subject, _ := identify(ctx, cfg.SignInKey); subject != "" {
+			saved, err := store.Get(ctx.Request.Context(), i18n.PreferenceKey(subject))
+			if err != nil {
+				logger.WithContext(log, ctx.Request.Context()).Warn("failed to get language preference", logger.Error(err))
+			} else if i18n.IsSupported(cast.ToString(saved)) {
+				lang = cast.ToString(saved)
+			}
+		}
+		if lang == "" {
+			lang = i18n.Negotiate(ctx.GetHeader("Accept-Language"))
+		}
+
+		ctx.Request = ctx.Request.WithContext(i18n.NewContext(ctx.Request.Context(), lang))
+		ctx.Header(i18n.Header, lang)
+		ctx.Writer.Header().Add("Vary", "Accept-Language")
+	}
+}
@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	v1 "myproject/admin-api-gateway/api/handlers/v1"
+	"myproject/admin-api-gateway/pkg/featureflag"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultMaintenanceMessage = "The service is under maintenance, try again later"
+	defaultReadOnlyMessage    = "The service is read-only for now, try again later"
+)
+
+// Maintenance rejects requests with a 503 while the maintenance flag, or for
+// mutating requests the read-only flag, is enabled for their path. Probes,
+// the admin API and the admin login stay available so that the flags can
+// always be turned off again.
+func Maintenance(flags *featureflag.Flags) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		path := ctx.Request.URL.Path
+		if isInfraPath(ctx.FullPath()) || maintenanceExempt(path) {
+			return
+		}
+
+		if flag, ok := flags.Applies(featureflag.Maintenance, path); ok {
+			abortUnavailable(ctx, v1.ErrorCodeMaintenance, flag.Message, defaultMaintenanceMessage)
+			return
+		}
+
+		if !isMutating(ctx.Request.Method) {
+			return
+		}
+		if flag, ok := flags.Applies(featureflag.ReadOnly, path); ok {
+			abortUnavailable(ctx, v1.ErrorCodeReadOnly, flag.Message, defaultReadOnlyMessage)
+		}
+	}
+}
+
+func maintenanceExempt(path string) bool {
+	return strings.HasPrefix(path, "/v1/admin/") ||
+		strings.HasPrefix(path, "/v1/swagger/") ||
+		path == "/v1/auth/login"
+}
+
+func isMutating(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+
+	return false
+}
+
+func abortUnavailable(ctx *gin.Context, code, message, fallback string) {
+	if message == "" {
+		message = fallback
+	}
+
+	v1.AbortWithError(ctx, http.StatusServiceUnavailable, code, message)
+}
@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	v1 "myproject/admin-api-gateway/api/handlers/v1"
+	"myproject/admin-api-gateway/config"
+	"myproject/admin-api-gateway/pkg/logger"
+	"myproject/admin-api-gateway/pkg/metrics"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Metrics records request counters and latency histograms by route template
+func Metrics() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		start := time.Now()
+		ctx.Next()
+
+		route := ctx.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		status := strconv.Itoa(ctx.Writer.Status())
+
+		metrics.HTTPRequests.WithLabelValues(ctx.Request.Method, route, status).Inc()
+		metrics.HTTPDuration.WithLabelValues(ctx.Request.Method, route, status).Observe(time.Since(start).Seconds())
+	}
+}
+
+// MetricsGuard protects the metrics endpoint, it is served only when enabled
+// in the config and only to peers from cfg.MetricsAllowedIPs.
+func MetricsGuard(cfg config.Config, log logger.Logger) gin.HandlerFunc {
+	var allowed []*net.IPNet
+	for _, item := range splitRules(cfg.MetricsAllowedIPs) {
+		if !strings.Contains(item, "/") {
+			if strings.Contains(item, ":") {
+				item += "/128"
+			} else {
+				item += "/32"
+			}
+		}
+
+		_, network, err := net.ParseCIDR(item)
+		if err != nil {
+			log.Error("invalid metrics allowlist entry", logger.String("entry", item), logger.Error(err))
+			continue
+		}
+		allowed = append(allowed, network)
+	}
+
+	return func(ctx *gin.Context) {
+		if !cfg.MetricsEnabled {
+			ctx.AbortWithStatus(http.StatusNotFound)
+			return
+		}
+
+		ip := net.ParseIP(ctx.RemoteIP())
+		for _, network := range allowed {
+			if ip != nil && network.Contains(ip) {
+				return
+			}
+		}
+
+		v1.AbortWithError(ctx, http.StatusForbidden, v1.ErrorCodeForbidden, "metrics are not available from your address")
+	}
+}
@@ -3,10 +3,11 @@ package middleware
 import (
 	"myproject/admin-api-gateway/api/handlers/tokens"
 	v1 "myproject/admin-api-gateway/api/handlers/v1"
-	"myproject/admin-api-gateway/api/models"
 	"myproject/admin-api-gateway/config"
+	"myproject/admin-api-gateway/pkg/metrics"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/casbin/casbin/v2"
 	"github.com/dgrijalva/jwt-go"
@@ -39,22 +40,18 @@ func Auth(casbin *casbin.Enforcer, cfg config.Config) gin.HandlerFunc {
 			casbinHandler.RequirePermission(ctx)
 			return
 		}
+
+		// handlers shape their responses by the role of the caller
+		identify(ctx, cfg.SignInKey)
 	}
 }
 
 func (c *CasbinHandler) GetRole(ctx *http.Request) (string, int) {
-	token := ctx.Header.Get("Authorization")
-	if token == "" {
+	cutToken := bearerToken(ctx)
+	if cutToken == "" {
 		return "unauthorized", http.StatusOK
 	}
 
-	var cutToken string
-	if strings.Contains(token, "Bearer") {
-		cutToken = strings.TrimPrefix(token, "Bearer ")
-	} else {
-		cutToken = token
-	}
-
 	claims, err := tokens.ExtractClaims(cutToken, []byte(c.cfg.SignInKey))
 	if err != nil {
 		return "unauthorized, token is invalid", http.StatusBadRequest
@@ -72,7 +69,9 @@ func (c *CasbinHandler) CheckPermission(ctx *http.Request) (bool, error) {
 	object := ctx.URL.Path
 	action := ctx.Method
 
+	start := time.Now()
 	response, err := c.enforcer.Enforce(role, object, action)
+	metrics.CasbinEnforceDuration.WithLabelValues(enforceResult(response, err)).Observe(time.Since(start).Seconds())
 	if err != nil {
 		return false, nil
 	}
@@ -81,15 +80,29 @@ func (c *CasbinHandler) CheckPermission(ctx *http.Request) (bool, error) {
 }
 
 func (c *CasbinHandler) RequirePermission(ctx *gin.Context) {
-	ctx.AbortWithStatusJSON(http.StatusMethodNotAllowed, models.StandardErrorModel{
-		Status:  v1.StatusMethodNotAllowed,
-		Message: "This method is not allowed to you",
-	})
+	v1.AbortWithError(ctx, http.StatusMethodNotAllowed, v1.StatusMethodNotAllowed, "This method is not allowed to you")
 }
 
 func (c *CasbinHandler) RequireRefresh(ctx *gin.Context) {
-	ctx.AbortWithStatusJSON(http.StatusUnauthorized, models.StandardErrorModel{
-		Status:  v1.ErrorCodeUnauthorized,
-		Message: "Access token is expired, refresh it.",
-	})
+	v1.AbortWithError(ctx, http.StatusUnauthorized, v1.ErrorCodeUnauthorized, "Access token is expired, refresh it.")
+}
+
+func bearerToken(ctx *http.Request) string {
+	token := ctx.Header.Get("Authorization")
+	if strings.Contains(token, "Bearer") {
+		return strings.TrimPrefix(token, "Bearer ")
+	}
+
+	return token
+}
+
+func enforceResult(allowed bool, err error) string {
+	switch {
+	case err != nil:
+		return "error"
+	case allowed:
+		return "allowed"
+	default:
+		return "denied"
+	}
 }
@@ -0,0 +1,235 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math"
+	v1 "myproject/admin-api-gateway/api/handlers/v1"
+	"myproject/admin-api-gateway/config"
+	"myproject/admin-api-gateway/pkg/logger"
+	"myproject/admin-api-gateway/storage/repo"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type limitRule struct {
+	name   string
+	limit  int
+	window time.Duration
+}
+
+// limitRules is replaced as a whole when the config is reloaded
+type limitRules struct {
+	enabled  bool
+	fallback *limitRule
+	roles    map[string]*limitRule
+	routes   map[string]*limitRule
+	// apiKeys holds the sha256 digests of the configured API keys
+	apiKeys   map[string]bool
+	signInKey string
+}
+
+type RateLimitHandler struct {
+	limiter repo.RateLimiterI
+	log     logger.Logger
+	rules   atomic.Pointer[limitRules]
+}
+
+// RateLimit throttles requests per API key, JWT subject or client IP.
+// A request is counted against its route rule (if any) and against the
+// rule of its casbin role, falling back to the default rule. The rules
+// follow config reloads.
+func RateLimit(limiter repo.RateLimiterI, watcher *config.Watcher, log logger.Logger) gin.HandlerFunc {
+	rateLimitHandler := &RateLimitHandler{
+		limiter: limiter,
+		log:     log,
+	}
+	rateLimitHandler.rules.Store(rateLimitHandler.loadRules(watcher.Current()))
+	watcher.Subscribe(func(_, next config.Config) {
+		rateLimitHandler.rules.Store(rateLimitHandler.loadRules(next))
+	})
+
+	return func(ctx *gin.Context) {
+		rules := rateLimitHandler.rules.Load()
+		if !rules.enabled || isInfraPath(ctx.FullPath()) {
+			return
+		}
+
+		identity, role := rateLimitHandler.identify(ctx, rules)
+
+		var (
+			current *limitRule
+			result  *repo.RateLimitResult
+		)
+		for _, rule := range rules.rulesFor(ctx.Request.Method, ctx.FullPath(), role) {
+			res, err := rateLimitHandler.limiter.Allow(ctx.Request.Context(), fmt.Sprintf("ratelimit:%s:%s", rule.name, identity), rule.limit, rule.window)
+			if err != nil {
+				logger.WithContext(rateLimitHandler.log, ctx.Request.Context()).Warn("rate limiter is unavailable, request is let through", logger.Error(err))
+				return
+			}
+
+			if result == nil || !res.Allowed || res.Remaining < result.Remaining {
+				current, result = rule, res
+			}
+			if !res.Allowed {
+				break
+			}
+		}
+
+		if result == nil {
+			return
+		}
+
+		reset := strconv.Itoa(int(math.Ceil(result.Reset.Seconds())))
+		ctx.Header("X-RateLimit-Limit", strconv.Itoa(current.limit))
+		ctx.Header("X-RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		ctx.Header("X-RateLimit-Reset", reset)
+
+		if !result.Allowed {
+			ctx.Header("Retry-After", reset)
+			v1.AbortWithError(ctx, http.StatusTooManyRequests, v1.ErrorCodeTooManyRequests, "Too many requests, try again later")
+		}
+	}
+}
+
+// isInfraPath reports whether the route serves probes or metrics scrapes,
+// which must never be throttled
+func isInfraPath(route string) bool {
+	switch route {
+	case "/healthz", "/readyz", "/metrics":
+		return true
+	}
+
+	return false
+}
+
+// identify returns the counter identity of the request and its role.
+// Configured API keys take precedence over JWT subjects, unknown keys are
+// ignored so that they cannot open new counters. Anonymous requests are
+// keyed by IP, X-Forwarded-For only counts from trusted proxies.
+func (r *RateLimitHandler) identify(ctx *gin.Context, rules *limitRules) (string, string) {
+	subject, role := identify(ctx, rules.signInKey)
+
+	if apiKey := ctx.GetHeader("X-API-Key"); apiKey != "" {
+		sum := sha256.Sum256([]byte(apiKey))
+		if digest := hex.EncodeToString(sum[:]); rules.apiKeys[digest] {
+			return "key:" + digest, role
+		}
+	}
+
+	if subject != "" {
+		return "sub:" + subject, role
+	}
+
+	return "ip:" + ctx.ClientIP(), role
+}
+
+func (r *limitRules) rulesFor(method, route, role string) []*limitRule {
+	var rules []*limitRule
+
+	if rule, ok := r.routes[method+" "+route]; ok {
+		rules = append(rules, rule)
+	} else if rule, ok := r.routes["* "+route]; ok {
+		rules = append(rules, rule)
+	}
+
+	if rule, ok := r.roles[role]; ok {
+		rules = append(rules, rule)
+	} else if r.fallback != nil {
+		rules = append(rules, r.fallback)
+	}
+
+	return rules
+}
+
+func (r *RateLimitHandler) loadRules(cfg config.Config) *limitRules {
+	rules := &limitRules{
+		enabled:   cfg.RateLimitEnabled,
+		roles:     make(map[string]*limitRule),
+		routes:    make(map[string]*limitRule),
+		apiKeys:   make(map[string]bool),
+		signInKey: cfg.SignInKey,
+	}
+
+	for _, key := range splitRules(cfg.RateLimitAPIKeys) {
+		sum := sha256.Sum256([]byte(key))
+		rules.apiKeys[hex.EncodeToString(sum[:])] = true
+	}
+
+	if cfg.RateLimitDefault != "" {
+		rule, err := parseLimitRule("default", cfg.RateLimitDefault)
+		if err != nil {
+			r.log.Error("invalid default rate limit", logger.Error(err))
+		} else {
+			rules.fallback = rule
+		}
+	}
+
+	for _, item := range splitRules(cfg.RateLimitRoles) {
+		role, limit, _ := strings.Cut(item, "=")
+		rule, err := parseLimitRule("role:"+strings.TrimSpace(role), limit)
+		if err != nil {
+			r.log.Error("invalid role rate limit", logger.String("rule", item), logger.Error(err))
+			continue
+		}
+		rules.roles[strings.TrimSpace(role)] = rule
+	}
+
+	for _, item := range splitRules(cfg.RateLimitRoutes) {
+		route, limit, _ := strings.Cut(item, "=")
+		method, path, ok := strings.Cut(strings.TrimSpace(route), " ")
+		if !ok {
+			r.log.Error("invalid route rate limit, expected 'METHOD /path=limit'", logger.String("rule", item))
+			continue
+		}
+		key := strings.ToUpper(method) + " " + strings.TrimSpace(path)
+		rule, err := parseLimitRule("route:"+key, limit)
+		if err != nil {
+			r.log.Error("invalid route rate limit", logger.String("rule", item), logger.Error(err))
+			continue
+		}
+		rules.routes[key] = rule
+	}
+
+	return rules
+}
+
+func splitRules(value string) []string {
+	var rules []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			rules = append(rules, item)
+		}
+	}
+
+	return rules
+}
+
+// parseLimitRule parses limits written as requests/window, e.g. 100/1m or 5/s.
+func parseLimitRule(name, value string) (*limitRule, error) {
+	count, period, ok := strings.Cut(strings.TrimSpace(value), "/")
+	if !ok {
+		return nil, fmt.Errorf("limit %q should be in the format 'requests/window'", value)
+	}
+
+	limit, err := strconv.Atoi(count)
+	if err != nil || limit <= 0 {
+		return nil, fmt.Errorf("limit %q should start with a positive number of requests", value)
+	}
+
+	if period != "" && (period[0] < '0' || period[0] > '9') {
+		period = "1" + period
+	}
+	window, err := time.ParseDuration(period)
+	if err != nil || window <= 0 {
+		return nil, fmt.Errorf("limit %q has an invalid window", value)
+	}
+
+	return &limitRule{name: name, limit: limit, window: window}, nil
+}
@@ -0,0 +1,25 @@
+package middleware
+
+import (
+	"myproject/admin-api-gateway/pkg/requestid"
+	"regexp"
+
+	"github.com/gin-gonic/gin"
+)
+
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._:\-]{1,128}$`)
+
+// RequestID accepts the client's X-Request-ID or generates a new one, stores it
+// in the request context and echoes it back in the response headers.
+func RequestID() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id := ctx.GetHeader(requestid.Header)
+		if !validRequestID.MatchString(id) {
+			id = requestid.New()
+		}
+
+		ctx.Request = ctx.Request.WithContext(requestid.NewContext(ctx.Request.Context(), id))
+		ctx.Header(requestid.Header, id)
+		ctx.Next()
+	}
+}
@@ -1,13 +1,27 @@
 package models
 
+import "time"
+
 type AdminReq struct {
-	Id       string `json:"id"`
-	FullName string `json:"full_name"`
-	Age      int64  `json:"age"`
-	Email    string `json:"email"`
-	UserName string `json:"username"`
-	Password string `json:"password"`
-	Role     string `json:"role"`
+	Id        string    `json:"id"`
+	FullName  string    `json:"full_name"`
+	Age       int64     `json:"age"`
+	Email     string    `json:"email"`
+	UserName  string    `json:"username"`
+	Password  string    `json:"password"`
+	Role      string    `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Admin is the public view of an admin
+type Admin struct {
+	Id        string    `json:"id"`
+	FullName  string    `json:"full_name"`
+	Age       int64     `json:"age"`
+	Email     string    `json:"email"`
+	UserName  string    `json:"username"`
+	Role      string    `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
 }
 
 type AdminUpdateReq struct {
@@ -75,6 +89,11 @@ type ListAdminsResp struct {
 	Admins []*AdminReq `json:"admins"`
 }
 
+type ListAdmins struct {
+	Count  int64    `json:"count"`
+	Admins []*Admin `json:"admins"`
+}
+
 type GetAdminReq struct {
 	Id string
 }
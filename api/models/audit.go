@@ -0,0 +1,44 @@
+package models
+
+import "time"
+
+type AuditEntry struct {
+	ID           int64                  `json:"id"`
+	CreatedAt    time.Time              `json:"created_at"`
+	ActorID      string                 `json:"actor_id"`
+	ActorRole    string                 `json:"actor_role"`
+	Action       string                 `json:"action"`
+	ResourceType string                 `json:"resource_type"`
+	ResourceID   string                 `json:"resource_id,omitempty"`
+	Method       string                 `json:"method"`
+	Route        string                 `json:"route"`
+	Status       int                    `json:"status"`
+	Before       interface{}            `json:"before,omitempty" swaggertype:"object"`
+	After        interface{}            `json:"after,omitempty" swaggertype:"object"`
+	Diff         map[string]AuditChange `json:"diff,omitempty"`
+	IP           string                 `json:"ip"`
+	UserAgent    string                 `json:"user_agent"`
+	RequestID    string                 `json:"request_id"`
+}
+
+type AuditChange struct {
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+type AuditFilter struct {
+	ActorID      string
+	ActorRole    string
+	Action       string
+	ResourceType string
+	ResourceID   string
+	From         time.Time
+	To           time.Time
+	Page         int
+	Limit        int
+}
+
+type ListAuditEntries struct {
+	Count   int64         `json:"count"`
+	Entries []*AuditEntry `json:"entries"`
+}
@@ -0,0 +1,16 @@
+package models
+
+import (
+	validation "github.com/go-ozzo/ozzo-validation/v3"
+)
+
+type LogLevel struct {
+	Level string `json:"level" example:"debug"`
+}
+
+func (l *LogLevel) Validate() error {
+	return validation.ValidateStruct(
+		l,
+		validation.Field(&l.Level, rule(RuleRequired, validation.Required), rule(RuleOneOf, validation.In("debug", "info", "warn", "error").Error("should be one of debug, info, warn or error"))),
+	)
+}
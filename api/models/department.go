@@ -29,8 +29,8 @@ type DepartmentResp struct {
 func (d *Department) Validate() error {
 	return validation.ValidateStruct(
 		d,
-		validation.Field(&d.ComeTime, validation.Required, validation.Match(regexp.MustCompile(`^\d{2}:\d{2}$`)).Error("should be in the format 'hh:mm'")),
-		validation.Field(&d.FinishTime, validation.Required, validation.Match(regexp.MustCompile(`^\d{2}:\d{2}$`)).Error("should be in the format 'hh:mm'")),
+		validation.Field(&d.ComeTime, rule(RuleRequired, validation.Required), rule(RuleFormat, validation.Match(regexp.MustCompile(`^\d{2}:\d{2}$`)).Error("should be in the format 'hh:mm'"))),
+		validation.Field(&d.FinishTime, rule(RuleRequired, validation.Required), rule(RuleFormat, validation.Match(regexp.MustCompile(`^\d{2}:\d{2}$`)).Error("should be in the format 'hh:mm'"))),
 	)
 }
 
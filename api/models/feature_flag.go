@@ -0,0 +1,42 @@
+package models
+
+import (
+	"regexp"
+
+	validation "github.com/go-ozzo/ozzo-validation/v3"
+)
+
+var flagNameRegexp = regexp.MustCompile(`^[a-z0-9_]+$`)
+
+type FeatureFlag struct {
+	Name      string   `json:"name"`
+	Enabled   bool     `json:"enabled"`
+	Message   string   `json:"message,omitempty"`
+	Routes    []string `json:"routes,omitempty"`
+	UpdatedBy string   `json:"updated_by,omitempty"`
+	UpdatedAt string   `json:"updated_at,omitempty"`
+}
+
+type FeatureFlagReq struct {
+	Enabled bool     `json:"enabled"`
+	Message string   `json:"message" example:"Doctors are being migrated, try again in an hour"`
+	Routes  []string `json:"routes" example:"/v1/doctor"`
+}
+
+func (f *FeatureFlagReq) Validate() error {
+	return validation.ValidateStruct(
+		f,
+		validation.Field(&f.Message, rule(RuleLength, validation.Length(0, 500))),
+		validation.Field(&f.Routes, validation.Each(rule(RuleRequired, validation.Required), rule(RuleFormat, validation.Match(regexp.MustCompile(`^/`)).Error("should start with '/'")))),
+	)
+}
+
+type ListFeatureFlags struct {
+	Count int            `json:"count"`
+	Flags []*FeatureFlag `json:"flags"`
+}
+
+// ValidFlagName reports whether name can be used as a feature flag name
+func ValidFlagName(name string) bool {
+	return flagNameRegexp.MatchString(name)
+}
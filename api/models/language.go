@@ -0,0 +1,16 @@
+package models
+
+import (
+	validation "github.com/go-ozzo/ozzo-validation/v3"
+)
+
+type Language struct {
+	Language string `json:"language" example:"uz"`
+}
+
+func (l *Language) Validate() error {
+	return validation.ValidateStruct(
+		l,
+		validation.Field(&l.Language, rule(RuleRequired, validation.Required), rule(RuleOneOf, validation.In("en", "uz", "ru").Error("should be one of en, uz or ru"))),
+	)
+}
@@ -0,0 +1,18 @@
+package models
+
+// Page is the envelope of the lists of the v2 API
+type Page struct {
+	Data  interface{} `json:"data"`
+	Total int64       `json:"total" example:"42"`
+	Page  int         `json:"page" example:"1"`
+	Limit int         `json:"limit" example:"10"`
+}
+
+// CursorPage is the envelope of the lists paginated by cursor, next and
+// prev are passed back as the cursor parameter and are empty at the ends
+type CursorPage struct {
+	Data  interface{} `json:"data"`
+	Limit int         `json:"limit" example:"10"`
+	Next  string      `json:"next,omitempty"`
+	Prev  string      `json:"prev,omitempty"`
+}
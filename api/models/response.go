@@ -1,21 +1,35 @@
 package models
 
+// ResponseError is the envelope of every error response
 type ResponseError struct {
-	Error interface{} `json:"error"`
-}
-
-type ServerError struct {
-	Status  string `json:"status"`
-	Message string `json:"message"`
+	Error StandardErrorModel `json:"error"`
 }
 
+// ValidationError describes why the value of one field was rejected
 type ValidationError struct {
-	Status      string `json:"status"`
-	Message     string `json:"message"`
-	UserMessage string `json:"user_message"`
+	Rule        string `json:"rule" example:"length"`
+	Message     string `json:"message" example:"the length must be between 3 and 50"`
+	UserMessage string `json:"user_message" example:"First name: the length must be between 3 and 50"`
 }
 
+// StandardErrorModel is the body of an error response. Fields maps the JSON
+// path of each rejected field to its error on VALIDATION_ERROR and
+// INVALID_JSON responses.
 type StandardErrorModel struct {
-	Status  string `json:"status"`
-	Message string `json:"message"`
+	Code      string                     `json:"code" example:"NOT_FOUND"`
+	Message   string                     `json:"message" example:"Resource not found"`
+	Details   []ErrorDetail              `json:"details,omitempty"`
+	Fields    map[string]ValidationError `json:"fields,omitempty"`
+	RequestID string                     `json:"request_id,omitempty" example:"7f0c2a4e-7d0e-4a53-9b8c-1f7e4a0b5c9d"`
+}
+
+type ErrorDetail struct {
+	Field       string `json:"field"`
+	Description string `json:"description"`
+}
+
+type ErrorCatalogEntry struct {
+	Code        string `json:"code"`
+	HTTPStatus  int    `json:"http_status"`
+	Description string `json:"description"`
 }
@@ -27,13 +27,9 @@ type LoginReqModel struct {
 	Password string `json:"password"`
 }
 
+// UserModel is a user with the access token issued for it
 type UserModel struct {
-	ID          string `json:"id"`
-	FirstName   string `json:"first_name"`
-	LastName    string `json:"last_name"`
-	BirthDate   string `json:"birth_date"`
-	Email       string `json:"email"`
-	Password    string `json:"password"`
+	UserResp
 	AccessToken string `json:"access_token"`
 }
 
@@ -45,16 +41,16 @@ type LoginRespModel struct {
 func (r *User) Validate() error {
 	return validation.ValidateStruct(
 		r,
-		validation.Field(&r.FirstName, validation.Required, validation.Length(3, 50), validation.Match(regexp.MustCompile(`^[A-Z][a-z]*$`)).Error(`should start with a capital letter and should only contain letters`)),
-		validation.Field(&r.LastName, validation.Required, validation.Length(3, 50), validation.Match(regexp.MustCompile(`^[A-Z][a-z]*$`)).Error(`should start with a capital letter and should only contain letters`)),
-		validation.Field(&r.Email, validation.Required, validation.Length(5, 100), is.Email),
+		validation.Field(&r.FirstName, rule(RuleRequired, validation.Required), rule(RuleLength, validation.Length(3, 50)), rule(RuleFormat, validation.Match(regexp.MustCompile(`^[A-Z][a-z]*$`)).Error(`should start with a capital letter and should only contain letters`))),
+		validation.Field(&r.LastName, rule(RuleRequired, validation.Required), rule(RuleLength, validation.Length(3, 50)), rule(RuleFormat, validation.Match(regexp.MustCompile(`^[A-Z][a-z]*$`)).Error(`should start with a capital letter and should only contain letters`))),
+		validation.Field(&r.Email, rule(RuleRequired, validation.Required), rule(RuleLength, validation.Length(5, 100)), rule(RuleEmail, is.Email)),
 		validation.Field(&r.Password,
-			validation.Required,
-			validation.Length(5, 30),
-			validation.Match(regexp.MustCompile(`\\d`)).Error(`should contain at least one digit`),
-			validation.Match(regexp.MustCompile(`^[a-zA-Z\\d]+$`)).Error(`should only contain letters (either lowercase or uppercase) and digits`),
+			rule(RuleRequired, validation.Required),
+			rule(RuleLength, validation.Length(5, 30)),
+			rule(RuleFormat, validation.Match(regexp.MustCompile(`\\d`)).Error(`should contain at least one digit`)),
+			rule(RuleFormat, validation.Match(regexp.MustCompile(`^[a-zA-Z\\d]+$`)).Error(`should only contain letters (either lowercase or uppercase) and digits`)),
 		),
-		validation.Field(&r.BirthDate, validation.Required, validation.Match(regexp.MustCompile(`^\\d{4}-\\d{2}-\\d{2}$`)).Error(`should be in the format 'yyyy-mm-dd'`)),
+		validation.Field(&r.BirthDate, rule(RuleRequired, validation.Required), rule(RuleFormat, validation.Match(regexp.MustCompile(`^\\d{4}-\\d{2}-\\d{2}$`)).Error(`should be in the format 'yyyy-mm-dd'`))),
 	)
 }
 
@@ -69,12 +65,7 @@ type RegisterUserModel struct {
 }
 
 type VerifyRespModel struct {
-	ID          string `json:"id"`
-	FirstName   string `json:"first_name"`
-	LastName    string `json:"last_name"`
-	BirthDate   string `json:"birth_date"`
-	Email       string `json:"email"`
-	Password    string `json:"password"`
+	UserResp
 	AccessToken string `json:"access_token"`
 }
 
@@ -86,20 +77,20 @@ type Status struct {
 	Message string `json:"message"`
 }
 
+// UserResp is the public view of a user
 type UserResp struct {
 	ID        string `json:"id"`
 	FirstName string `json:"first_name"`
 	LastName  string `json:"last_name"`
 	BirthDate string `json:"birth_date"`
 	Email     string `json:"email"`
-	Password  string `json:"password"`
 	CreatedAt string `json:"created_at"`
 	UpdatedAt string `json:"updated_at"`
 }
 
 type ListUsersResp struct {
-	Count int64
-	Users []*UserResp
+	Count int64       `json:"count"`
+	Users []*UserResp `json:"users"`
 }
 
 type ChangePasswordReq struct {
@@ -110,9 +101,9 @@ type ChangePasswordReq struct {
 func (c *ChangePasswordReq) Validate() error {
 	return validation.ValidateStruct(
 		c,
-		validation.Field(&c.NewPassword, validation.Required, validation.Length(5, 30),
-			validation.Match(regexp.MustCompile(`\\d`)).Error(`should contain at least one digit`),
-			validation.Match(regexp.MustCompile(`^[a-zA-Z\\d]+$`)).Error(`should only contain letters (either lowercase or uppercase) and digits`),
+		validation.Field(&c.NewPassword, rule(RuleRequired, validation.Required), rule(RuleLength, validation.Length(5, 30)),
+			rule(RuleFormat, validation.Match(regexp.MustCompile(`\\d`)).Error(`should contain at least one digit`)),
+			rule(RuleFormat, validation.Match(regexp.MustCompile(`^[a-zA-Z\\d]+$`)).Error(`should only contain letters (either lowercase or uppercase) and digits`)),
 		),
 	)
 }
@@ -0,0 +1,53 @@
+package models
+
+import (
+	validation "github.com/go-ozzo/ozzo-validation/v3"
+)
+
+// Rule ids sent to clients in the "rule" field of a field error
+const (
+	RuleRequired    = "required"
+	RuleLength      = "length"
+	RuleFormat      = "format"
+	RuleOneOf       = "one_of"
+	RuleEmail       = "email"
+	RuleType        = "type"
+	RuleUnsupported = "unsupported"
+	RuleInvalid     = "invalid"
+)
+
+// RuleError is the error of a validation rule tagged with rule
+type RuleError struct {
+	Rule string
+	Err  error
+}
+
+func (e RuleError) Error() string {
+	return e.Err.Error()
+}
+
+func (e RuleError) Unwrap() error {
+	return e.Err
+}
+
+type idRule struct {
+	id   string
+	rule validation.Rule
+}
+
+func (r idRule) Validate(value interface{}) error {
+	err := r.rule.Validate(value)
+	if err == nil {
+		return nil
+	}
+	if _, ok := err.(validation.InternalError); ok {
+		return err
+	}
+	return RuleError{Rule: r.id, Err: err}
+}
+
+// rule tags r with the id reported to clients when it fails, rules left
+// untagged are reported as RuleInvalid
+func rule(id string, r validation.Rule) validation.Rule {
+	return idRule{id: id, rule: r}
+}
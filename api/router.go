@@ -1,12 +1,14 @@
 package api
 
 import (
-	"fmt"
 	_ "myproject/admin-api-gateway/api/docs"
+	"myproject/admin-api-gateway/api/handlers/health"
 	"myproject/admin-api-gateway/api/handlers/tokens"
 	v1 "myproject/admin-api-gateway/api/handlers/v1"
 	"myproject/admin-api-gateway/api/middleware"
 	"myproject/admin-api-gateway/config"
+	"myproject/admin-api-gateway/pkg/audit"
+	"myproject/admin-api-gateway/pkg/featureflag"
 	"myproject/admin-api-gateway/pkg/logger"
 	"myproject/admin-api-gateway/services"
 	"myproject/admin-api-gateway/storage/postgresrepo"
@@ -16,17 +18,24 @@ import (
 	ginSwagger "github.com/swaggo/gin-swagger"
 
 	"github.com/casbin/casbin/v2"
-	"github.com/casbin/casbin/v2/util"
-	gormadapter "github.com/casbin/gorm-adapter/v3"
 	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 )
 
 type Option struct {
 	InMemory       repo.InMemoryStorageI
-	Cfg            config.Config
+	RateLimiter    repo.RateLimiterI
+	FeatureFlags   repo.FeatureFlagsI
+	Flags          *featureflag.Flags
+	AuditStorage   postgresrepo.AuditStorageI
+	Auditor        *audit.Recorder
+	Config         *config.Watcher
 	Logger         logger.Logger
 	ServiceManager services.IServiceManager
 	Postgres       postgresrepo.AdminStorageI
+	Casbin         *casbin.Enforcer
+	Health         *health.Handler
 }
 
 // Constructor
@@ -38,33 +47,30 @@ type Option struct {
 // @in header
 // @name Authorization
 func New(option Option) *gin.Engine {
-	psqlString := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=disable",
-		option.Cfg.PostgresHost,
-		option.Cfg.PostgresPort,
-		option.Cfg.PostgresUser,
-		option.Cfg.PostgresPassword,
-		option.Cfg.PostgresDatabase)
-
-	adapter, err := gormadapter.NewAdapter("postgres", psqlString, true)
-	if err != nil {
-		option.Logger.Fatal("error while creating a new adapter for casbin")
-	}
+	router := gin.New()
+	cfg := option.Config.Current()
 
-	casbinEnforcer, err := casbin.NewEnforcer(option.Cfg.AuthConfigPath, adapter)
-	if err != nil {
-		option.Logger.Error("erro while creating a new casbin enforcer")
+	// X-Forwarded-For is only honoured from the configured proxies, the
+	// client IP keys the rate limits of anonymous callers
+	if err := router.SetTrustedProxies(cfg.TrustedProxyList()); err != nil {
+		option.Logger.Error("invalid trusted proxies, none is trusted", logger.Error(err))
+		_ = router.SetTrustedProxies(nil)
 	}
 
-	casbinEnforcer.GetRoleManager().AddMatchingFunc("keyMatch", util.KeyMatch)
-	casbinEnforcer.GetRoleManager().AddMatchingFunc("keyMatch3", util.KeyMatch3)
-
-	router := gin.New()
-
-	router.Use(gin.Logger())
-	router.Use(gin.Recovery())
+	router.Use(middleware.RequestID())
+	router.Use(otelgin.Middleware("admin-api-gateway"))
+	router.Use(middleware.AccessLog(option.Logger, option.Config))
+	router.Use(middleware.Metrics())
+	router.Use(gin.CustomRecovery(v1.Recovery))
+	router.Use(middleware.Language(option.InMemory, cfg, option.Logger))
+	router.Use(middleware.SecurityHeaders(cfg))
+	router.Use(middleware.CORS(cfg, option.Logger))
+	router.Use(middleware.Maintenance(option.Flags))
+	router.Use(middleware.RateLimit(option.RateLimiter, option.Config, option.Logger))
+	router.Use(middleware.Audit(option.Auditor, cfg))
 
 	jwtHandler := tokens.JWTHandler{
-		SignInKey: option.Cfg.SignInKey,
+		SignInKey: cfg.SignInKey,
 		Log:       option.Logger,
 	}
 
@@ -72,15 +78,29 @@ func New(option Option) *gin.Engine {
 		InMemoryStorage: option.InMemory,
 		Log:             option.Logger,
 		ServiceManager:  option.ServiceManager,
-		Cfg:             option.Cfg,
+		Cfg:             cfg,
+		Config:          option.Config,
 		JwtHandler:      jwtHandler,
-		Casbin:          casbinEnforcer,
+		Casbin:          option.Casbin,
 		Postgres:        option.Postgres,
+		FeatureFlags:    option.FeatureFlags,
+		Flags:           option.Flags,
+		AuditStorage:    option.AuditStorage,
 	})
 
+	cache := middleware.NewResponseCache(option.InMemory, option.Config, option.Logger)
+	idempotency := middleware.Idempotency(option.InMemory, cfg, option.Logger)
+
+	router.NoRoute(v1.NoRoute)
+	router.GET("/healthz", option.Health.Live)
+	router.GET("/readyz", option.Health.Ready)
+	router.GET("/metrics", middleware.MetricsGuard(cfg, option.Logger), gin.WrapH(promhttp.Handler()))
+
 	api := router.Group("/v1")
 
-	router.Static("/media", "./media")                    //unauthorized
+	router.Static("/media", "./media") //unauthorized
+
+	api.GET("/errors", handlerV1.ListErrorCodes) //unauthorized
 
 	//Rbac
 	api.GET("/rbac/roles", handlerV1.ListRoles)                 //superadmin
@@ -89,19 +109,19 @@ func New(option Option) *gin.Engine {
 	api.DELETE("/rbac/delete/policy", handlerV1.DeletePolicy)   //superadmin
 
 	//Auth
-	api.POST("/auth/create", handlerV1.CreateAdmin)            //superadmin
-	api.DELETE("/auth/delete", handlerV1.DeleteAdmin)          //superadmin
-	api.POST("/auth/login", handlerV1.LoginAdmin)              //unauthorized
-	api.GET("/auth/admins/:page/:limit", handlerV1.ListAdmins) //admin
-	api.GET("auth/get/:id", handlerV1.GetAdmin)                //admin
-	api.PUT("auth/update", handlerV1.Update)                   //admin
+	api.POST("/auth/create", idempotency, handlerV1.CreateAdmin) //superadmin
+	api.DELETE("/auth/delete", handlerV1.DeleteAdmin)            //superadmin
+	api.POST("/auth/login", handlerV1.LoginAdmin)                //unauthorized
+	api.GET("/auth/admins/:page/:limit", handlerV1.ListAdmins)   //admin
+	api.GET("auth/get/:id", handlerV1.GetAdmin)                  //admin
+	api.PUT("auth/update", handlerV1.Update)                     //admin
 
 	//User
-	api.Use(middleware.Auth(casbinEnforcer, option.Cfg))
+	api.Use(middleware.Auth(option.Casbin, cfg))
 	api.POST("/register", handlerV1.Register)                   //unauthorized
 	api.GET("/verify/:email/:code", handlerV1.Verify)           //unauthorized
 	api.POST("/login", handlerV1.Login)                         //unauthorized
-	api.POST("/user/create", handlerV1.CreateUser)              //admin
+	api.POST("/user/create", idempotency, handlerV1.CreateUser) //admin
 	api.GET("/user/:id", handlerV1.GetUserById)                 //user
 	api.PUT("/user/update/:id", handlerV1.UpdateUser)           //user
 	api.DELETE("/user/delete/:id", handlerV1.DeleteUser)        //user
@@ -109,40 +129,62 @@ func New(option Option) *gin.Engine {
 	api.POST("/user/password", handlerV1.ChangePassword)        //user
 	api.POST("/user/refresh", handlerV1.UpdateRefreshToken)     //user
 
+	//Profile
+	api.GET("/profile/language", handlerV1.GetLanguage)    //user, doctor, admin, superadmin
+	api.PUT("/profile/language", handlerV1.UpdateLanguage) //user, doctor, admin, superadmin
+
 	//Doctor
-	api.POST("/doctor/register", handlerV1.RegisterDoctor)                               //unauthorized
-	api.GET("/doctor/verify/{email}/{code}", handlerV1.VerifyDoctor)                     //unauthorized
-	api.POST("/doctor/login", handlerV1.LoginDoctor)                                     //unauthorized
-	api.POST("/doctor/create", handlerV1.CreateDoctor)                                   //admin, superadmin
-	api.GET("/doctor/:id", handlerV1.GetDoctorById)                                      //doctor, user, operator, admin, superadmin
-	api.PUT("/doctor/update/:id", handlerV1.UpdateDoctor)                                //doctor, admin, superadmin
-	api.DELETE("/doctor/delete/:id", handlerV1.DeleteDoctor)                             //doctor, admin, superadmin
-	api.GET("/doctors/:page/:limit", handlerV1.ListDoctors)                              //user, doctor, operator, admin, superadmin
-	api.GET("/doctors/:page/:limit/:department_id", handlerV1.ListDoctorsByDepartmentId) //user, doctor, operator, admin, superadmin
-	router.POST("/doctor/upload", handlerV1.UploadFile)                                     //unauthorized
+	api.POST("/doctor/register", handlerV1.RegisterDoctor)                                        //unauthorized
+	api.GET("/doctor/verify/{email}/{code}", cache.Invalidate("doctors"), handlerV1.VerifyDoctor) //unauthorized
+	api.POST("/doctor/login", handlerV1.LoginDoctor)                                              //unauthorized
+	api.POST("/doctor/create", idempotency, cache.Invalidate("doctors"), handlerV1.CreateDoctor)  //admin, superadmin
+	api.GET("/doctor/:id", handlerV1.GetDoctorById)                                               //doctor, user, operator, admin, superadmin
+	api.PUT("/doctor/update/:id", cache.Invalidate("doctors"), handlerV1.UpdateDoctor)            //doctor, admin, superadmin
+	api.DELETE("/doctor/delete/:id", cache.Invalidate("doctors"), handlerV1.DeleteDoctor)         //doctor, admin, superadmin
+	api.GET("/doctors/:page/:limit", cache.Cached("doctors"), handlerV1.ListDoctors)              //user, doctor, operator, admin, superadmin
+	api.GET("/doctors/:page/:limit/:department_id", handlerV1.ListDoctorsByDepartmentId)          //user, doctor, operator, admin, superadmin
+	router.POST("/doctor/upload", handlerV1.UploadFile)                                           //unauthorized
 
 	//Department
-	api.POST("/department/create", handlerV1.CreateDepartment)       //admin, superadmin
-	api.GET("/department/:id", handlerV1.GetDepartmentById)          //doctor, user, admin, operator, superadmin
-	api.PUT("/department/update/:id", handlerV1.UpdateDepartment)    //admin, superadmin
-	api.DELETE("/department/delete/:id", handlerV1.DeleteDepartment) //admin, superadmin
-	api.GET("/departments/:page/:limit", handlerV1.ListDepartments)  //user, doctor, operator, admin, superadmin
-	api.POST("/department/upload", handlerV1.UploadDepartmentFile)   //unauthorized
+	api.POST("/department/create", idempotency, cache.Invalidate("departments"), handlerV1.CreateDepartment) //admin, superadmin
+	api.GET("/department/:id", cache.Cached("departments"), handlerV1.GetDepartmentById)                     //doctor, user, admin, operator, superadmin
+	api.PUT("/department/update/:id", cache.Invalidate("departments"), handlerV1.UpdateDepartment)           //admin, superadmin
+	api.DELETE("/department/delete/:id", cache.Invalidate("departments"), handlerV1.DeleteDepartment)        //admin, superadmin
+	api.GET("/departments/:page/:limit", cache.Cached("departments"), handlerV1.ListDepartments)             //user, doctor, operator, admin, superadmin
+	api.POST("/department/upload", handlerV1.UploadDepartmentFile)                                           //unauthorized
 
 	//Specialization
-	api.POST("/specialization/create", handlerV1.CreateSpecializaion)                                    //admin, superadmin
-	api.GET("/specialization/:id", handlerV1.GetSpecializationById)                                      //user, doctor, operator, admin, superadmin
-	api.PUT("/specialization/update/:id", handlerV1.UpdateSpecialization)                                //admin, superadmin
-	api.DELETE("/specialization/delete/:id", handlerV1.DeleteSpecialization)                             //admin, superadmin
-	api.GET("/specializations/:page/:limit", handlerV1.ListSpecializations)                              //user, doctor, operator, admin, superadmin
-	api.GET("/specializations/:page/:limit/:department_id", handlerV1.ListSpecializationsByDepartmentId) //user, doctor, operator, admin, superadmin
+	api.POST("/specialization/create", cache.Invalidate("specializations"), handlerV1.CreateSpecializaion)                                //admin, superadmin
+	api.GET("/specialization/:id", handlerV1.GetSpecializationById)                                                                       //user, doctor, operator, admin, superadmin
+	api.PUT("/specialization/update/:id", cache.Invalidate("specializations"), handlerV1.UpdateSpecialization)                            //admin, superadmin
+	api.DELETE("/specialization/delete/:id", cache.Invalidate("specializations"), handlerV1.DeleteSpecialization)                         //admin, superadmin
+	api.GET("/specializations/:page/:limit", cache.Cached("specializations"), handlerV1.ListSpecializations)                              //user, doctor, operator, admin, superadmin
+	api.GET("/specializations/:page/:limit/:department_id", cache.Cached("specializations"), handlerV1.ListSpecializationsByDepartmentId) //user, doctor, operator, admin, superadmin
 
 	//Specialization price
-	api.POST("/specprice/create", handlerV1.CreateSpecPrice)       //admin, superadmin
-	api.GET("/specprice/:id", handlerV1.GetSpecPriceById)          //user, doctor, operator, admin, superadmin
-	api.PUT("/specprice/update/:id", handlerV1.UpdateSpecPrice)    //admin, superadmin
-	api.DELETE("/specprice/delete/:id", handlerV1.DeleteSpecPrice) //admin, superadmin
-	api.GET("/specprices/:page/:limit", handlerV1.ListSpecPrices)  //user, doctor, operator, admin, superadmin
+	api.POST("/specprice/create", idempotency, cache.Invalidate("spec_prices", "specializations"), handlerV1.CreateSpecPrice) //admin, superadmin
+	api.GET("/specprice/:id", handlerV1.GetSpecPriceById)                                                                     //user, doctor, operator, admin, superadmin
+	api.PUT("/specprice/update/:id", cache.Invalidate("spec_prices", "specializations"), handlerV1.UpdateSpecPrice)           //admin, superadmin
+	api.DELETE("/specprice/delete/:id", cache.Invalidate("spec_prices", "specializations"), handlerV1.DeleteSpecPrice)        //admin, superadmin
+	api.GET("/specprices/:page/:limit", cache.Cached("spec_prices"), handlerV1.ListSpecPrices)                                //user, doctor, operator, admin, superadmin
+
+	//Admin
+	api.GET("/admin/config", handlerV1.GetConfig)                 //superadmin
+	api.GET("/admin/log-level", handlerV1.GetLogLevel)            //superadmin
+	api.PUT("/admin/log-level", handlerV1.UpdateLogLevel)         //superadmin
+	api.GET("/admin/flags", handlerV1.ListFeatureFlags)           //superadmin
+	api.PUT("/admin/flags/:name", handlerV1.SetFeatureFlag)       //superadmin
+	api.DELETE("/admin/flags/:name", handlerV1.DeleteFeatureFlag) //superadmin
+	api.GET("/admin/audit", handlerV1.ListAuditLog)               //superadmin
+	api.GET("/admin/audit/export", handlerV1.ExportAuditLog)      //superadmin
+
+	//v2 lists: query based pagination, filtering and sorting
+	v2 := router.Group("/v2")
+	v2.Use(middleware.Auth(option.Casbin, cfg))
+	v2.GET("/doctors", cache.Cached("doctors"), handlerV1.ListDoctorsV2)             //user, doctor, operator, admin, superadmin
+	v2.GET("/departments", cache.Cached("departments"), handlerV1.ListDepartmentsV2) //user, doctor, operator, admin, superadmin
+	v2.GET("/users", handlerV1.ListUsersV2)                                          //admin, superadmin
+	v2.GET("/admins", handlerV1.ListAdminsV2)                                        //admin, superadmin
 
 	url := ginSwagger.URL("swagger/doc.json")
 	api.GET("swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler, url))
@@ -1,29 +1,91 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"myproject/admin-api-gateway/api"
+	"myproject/admin-api-gateway/api/handlers/health"
 	"myproject/admin-api-gateway/config"
+	"myproject/admin-api-gateway/pkg/audit"
 	"myproject/admin-api-gateway/pkg/db"
+	"myproject/admin-api-gateway/pkg/featureflag"
 	"myproject/admin-api-gateway/pkg/logger"
+	"myproject/admin-api-gateway/pkg/metrics"
+	"myproject/admin-api-gateway/pkg/tracing"
 	"myproject/admin-api-gateway/services"
 	"myproject/admin-api-gateway/storage/postgres"
 	"myproject/admin-api-gateway/storage/redis"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	rds "github.com/gomodule/redigo/redis"
 )
 
 func main() {
-	cfg := config.Load()
+	cfg, err := config.Load(os.Args[1:])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "invalid configuration:", err)
+		os.Exit(1)
+	}
+
 	log := logger.New(cfg.LogLevel, "admin-api-gateway")
+	defer logger.Cleanup(log)
+
+	if err := run(cfg, os.Args[1:], log); err != nil {
+		log.Fatal("admin-api-gateway stopped with an error", logger.Error(err))
+	}
+}
+
+// run starts the gateway and blocks until SIGINT or SIGTERM. Resources are
+// released by the deferred calls in reverse order of acquisition: the audit
+// recorder flushes first while the DB is still open, then the gRPC
+// connections, the redis pool, the casbin adapter and the DB are closed.
+// SIGHUP reloads the runtime part of the config, see config.Watcher.
+func run(cfg config.Config, args []string, log logger.Logger) error {
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
 
-	serviceManager, err := services.NewServiceManager(cfg)
+	watcher := config.NewWatcher(cfg, args, log)
+	watcher.Subscribe(func(prev, next config.Config) {
+		if prev.LogLevel == next.LogLevel {
+			return
+		}
+		if err := logger.SetLevel(log, next.LogLevel); err != nil {
+			log.Error("cannot apply reloaded log level", logger.Error(err))
+		}
+	})
+	go watcher.Run(ctx, time.Second*time.Duration(cfg.ConfigReloadInterval))
+
+	shutdownTracing, err := tracing.Init(ctx, cfg, "admin-api-gateway")
 	if err != nil {
-		log.Error("gRPC dial error", logger.Error(err))
-		return
+		return fmt.Errorf("cannot initialize tracing: %w", err)
 	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*time.Duration(cfg.ShutdownTimeout))
+		defer cancel()
+		closeWithLog(log, "tracer provider", func() error { return shutdownTracing(ctx) })
+	}()
 
-	redisPool := rds.Pool{
+	db, cleanUpDB, err := db.ConnectToDB(cfg)
+	if err != nil {
+		return fmt.Errorf("cannot connect to DB: %w", err)
+	}
+	defer func() {
+		cleanUpDB()
+		log.Info("postgres connection is closed")
+	}()
+
+	casbinEnforcer, closeAdapter, err := api.NewEnforcer(cfg)
+	if err != nil {
+		return fmt.Errorf("cannot create casbin enforcer: %w", err)
+	}
+	defer closeWithLog(log, "casbin adapter", closeAdapter)
+
+	redisPool := &rds.Pool{
 		MaxIdle:   80,
 		MaxActive: 12000,
 		Dial: func() (rds.Conn, error) {
@@ -34,23 +96,99 @@ func main() {
 			return c, nil
 		},
 	}
+	defer closeWithLog(log, "redis pool", redisPool.Close)
 
-	db, _, err := db.ConnectToDB(cfg)
+	serviceManager, err := services.NewServiceManager(cfg, log)
 	if err != nil {
-		log.Fatal("cannot connect to DB", logger.Error(err))
-		return
+		return fmt.Errorf("gRPC dial error: %w", err)
+	}
+	defer closeWithLog(log, "gRPC connections", serviceManager.Close)
+
+	metrics.RegisterRedisPool(redisPool)
+	metrics.RegisterDB(db, cfg.PostgresDatabase)
+
+	inMemory := redis.NewRedisRepo(redisPool)
+
+	auditStorage := postgres.NewAuditRepo(db)
+	auditor := audit.NewRecorder(auditStorage, log, cfg.AuditBufferSize, cfg.AuditBatchSize,
+		time.Millisecond*time.Duration(cfg.AuditFlushInterval))
+	defer closeWithLog(log, "audit recorder", auditor.Close)
+
+	featureFlags := redis.NewFeatureFlags(redisPool)
+	flags := featureflag.New(featureFlags, log)
+	go flags.Run(ctx, time.Second*time.Duration(cfg.FeatureFlagsRefreshInterval))
+
+	healthHandler := health.New(time.Millisecond * time.Duration(cfg.HealthCheckTimeout))
+	healthHandler.AddCheck("postgres", db.PingContext)
+	healthHandler.AddCheck("redis", inMemory.Ping)
+	for _, backend := range serviceManager.Backends() {
+		healthHandler.AddCheck(backend+"_service", func(ctx context.Context) error {
+			return serviceManager.Ping(ctx, backend)
+		})
 	}
+	healthHandler.AddCheck("casbin", api.EnforcerCheck(casbinEnforcer))
 
-	server := api.New(api.Option{
-		InMemory:       redis.NewRedisRepo(&redisPool),
-		Cfg:            cfg,
+	router := api.New(api.Option{
+		InMemory:       inMemory,
+		RateLimiter:    redis.NewRateLimiter(redisPool),
+		FeatureFlags:   featureFlags,
+		Flags:          flags,
+		AuditStorage:   auditStorage,
+		Auditor:        auditor,
+		Config:         watcher,
 		Logger:         log,
 		ServiceManager: serviceManager,
 		Postgres:       postgres.NewAdminRepo(db),
+		Casbin:         casbinEnforcer,
+		Health:         healthHandler,
 	})
 
-	if err := server.Run(cfg.HTTPPort); err != nil {
-		log.Fatal("cannot run http server", logger.Error(err))
+	servers, err := newHTTPServers(ctx, cfg, log, router)
+	if err != nil {
+		return fmt.Errorf("cannot configure http server: %w", err)
+	}
+
+	serverErr := make(chan error, len(servers))
+	for _, s := range servers {
+		go func(s httpServer) {
+			log.Info(s.name+" is listening", logger.String("address", s.server.Addr))
+			if err := s.serve(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				serverErr <- fmt.Errorf("cannot run %s: %w", s.name, err)
+			}
+		}(s)
+	}
+
+	healthHandler.SetReady(true)
+
+	select {
+	case err := <-serverErr:
+		return err
+	case <-ctx.Done():
+	}
+
+	// Stop advertising readiness first and give the load balancer time
+	// to notice before the listener is closed
+	log.Info("shutdown signal received, draining connections")
+	healthHandler.SetReady(false)
+	time.Sleep(time.Second * time.Duration(cfg.ShutdownDelay))
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), time.Second*time.Duration(cfg.ShutdownTimeout))
+	defer cancel()
+
+	for _, s := range servers {
+		if err := s.server.Shutdown(shutdownCtx); err != nil {
+			return fmt.Errorf("%s did not drain in time: %w", s.name, err)
+		}
+		log.Info(s.name + " is stopped")
+	}
+
+	return nil
+}
+
+func closeWithLog(log logger.Logger, name string, closeFunc func() error) {
+	if err := closeFunc(); err != nil {
+		log.Error("cannot close "+name, logger.Error(err))
 		return
 	}
+	log.Info(name + " is closed")
 }
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	"myproject/admin-api-gateway/config"
+	"myproject/admin-api-gateway/pkg/logger"
+	"myproject/admin-api-gateway/pkg/tlsutil"
+	"net"
+	"net/http"
+	"time"
+
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+)
+
+type httpServer struct {
+	name   string
+	server *http.Server
+	serve  func() error
+}
+
+// newHTTPServers builds the API server and, when TLS is on and a redirect
+// port is set, a plain HTTP server that sends clients to HTTPS. Certificates
+// are reloaded until ctx is done.
+func newHTTPServers(ctx context.Context, cfg config.Config, log logger.Logger, handler http.Handler) ([]httpServer, error) {
+	server := &http.Server{
+		Addr:              cfg.HTTPPort,
+		Handler:           handler,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	if !cfg.HTTPTLS.Enabled {
+		if cfg.HTTPH2C {
+			server.Handler = h2c.NewHandler(handler, &http2.Server{})
+		}
+		return []httpServer{{name: "http server", server: server, serve: server.ListenAndServe}}, nil
+	}
+
+	reloader, err := tlsutil.NewReloader(cfg.HTTPTLS.CertFile, cfg.HTTPTLS.KeyFile, cfg.HTTPTLS.CAFile, log)
+	if err != nil {
+		return nil, err
+	}
+	go reloader.Run(ctx, time.Second*time.Duration(cfg.TLSReloadInterval))
+
+	// HTTP/2 is negotiated through ALPN, the certificate comes from the reloader
+	server.TLSConfig = reloader.ServerConfig()
+	servers := []httpServer{{
+		name:   "https server",
+		server: server,
+		serve:  func() error { return server.ListenAndServeTLS("", "") },
+	}}
+
+	if cfg.HTTPRedirectPort != "" {
+		redirect := &http.Server{
+			Addr:              cfg.HTTPRedirectPort,
+			Handler:           redirectToHTTPS(cfg.HTTPPort),
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+		servers = append(servers, httpServer{name: "https redirect server", server: redirect, serve: redirect.ListenAndServe})
+	}
+
+	return servers, nil
+}
+
+// redirectToHTTPS answers every request with a permanent redirect to the
+// same URL on the HTTPS port
+func redirectToHTTPS(httpsAddr string) http.Handler {
+	_, httpsPort, _ := net.SplitHostPort(httpsAddr)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host := r.Host
+		if h, _, err := net.SplitHostPort(r.Host); err == nil {
+			host = h
+		}
+		if httpsPort != "" && httpsPort != "443" {
+			host = net.JoinHostPort(host, httpsPort)
+		}
+
+		http.Redirect(w, r, "https://"+host+r.URL.RequestURI(), http.StatusPermanentRedirect)
+	})
+}
@@ -1,11 +1,14 @@
 package config
 
 import (
-	"os"
-
 	"github.com/spf13/cast"
 )
 
+const (
+	defaultSignInKey        = "dev-sign-in-key-change-me"
+	defaultPostgresPassword = "postgres"
+)
+
 type Config struct {
 	Environment string
 
@@ -13,85 +16,249 @@ type Config struct {
 	PostgresPort     int
 	PostgresUser     string
 	PostgresDatabase string
-	PostgresPassword string
+	PostgresPassword string `secret:"true"`
 
 	RedisHost string
 	RedisPort int
 
 	UserServiceHost string
 	UserServicePort int
+	UserServiceTLS  TLSConfig
 
 	HealthcareServiceHost string
 	HealthcareServicePort int
+	HealthcareServiceTLS  TLSConfig
+
+	TLSReloadInterval    int //seconds between checks of the certificate files
+	ConfigReloadInterval int //seconds between checks of the config file, 0 to reload on SIGHUP only
 
 	BookingServiceHost string
 	BookingServicePort int
 
+	GRPCRetryMaxAttempts    int //attempts of idempotent calls, the first one included
+	GRPCRetryInitialBackoff int //milliseconds
+	GRPCRetryMaxBackoff     int //milliseconds
+	GRPCKeepaliveTime       int //seconds of inactivity before a ping
+	GRPCKeepaliveTimeout    int //seconds to wait for the ping ack
+	GRPCConnectBackoffMax   int //seconds between reconnection attempts at most
+	GRPCBreakerFailures     int //consecutive failures that open the breaker
+	GRPCBreakerOpenTimeout  int //seconds before a trial call is let through
+
 	CtxTimeout int
 
-	LogLevel string
+	LogLevel string `reload:"true"`
 	HTTPPort string
 
-	SignInKey           string
-	AccessTokenTimeOut  int
-	RefreshTokenTimeOut int
+	HTTPTLS          TLSConfig
+	HTTPH2C          bool   //serve HTTP/2 without TLS, for traffic inside the cluster
+	HTTPRedirectPort string //plain HTTP port redirecting to HTTPS, empty to disable
+
+	ShutdownTimeout int //seconds to drain in-flight requests
+	ShutdownDelay   int //seconds to keep serving after readiness is flipped
+
+	HealthCheckTimeout int //milliseconds per readiness dependency check
+
+	SignInKey           string `secret:"true"`
+	AccessTokenTimeout  int    //seconds
+	RefreshTokenTimeout int    //seconds
 
 	AuthConfigPath string
 
 	SendEmailFrom string
-	EmailCode     string
+	EmailCode     string `secret:"true"`
+
+	RateLimitEnabled bool   `reload:"true"`
+	RateLimitDefault string `reload:"true"`               //requests/window, e.g. 100/1m
+	RateLimitRoles   string `reload:"true"`               //role=requests/window, comma separated
+	RateLimitRoutes  string `reload:"true"`               //METHOD /route=requests/window, comma separated
+	RateLimitAPIKeys string `reload:"true" secret:"true"` //comma separated, other X-API-Key values are ignored
+
+	TrustedProxies string //IPs or CIDRs whose X-Forwarded-For is trusted, comma separated, empty to trust none
+
+	CORSAllowedOrigins   string //comma separated, * for any, https://*.example.com for subdomains
+	CORSAllowedMethods   string
+	CORSAllowedHeaders   string
+	CORSExposedHeaders   string
+	CORSAllowCredentials bool
+	CORSMaxAge           int //seconds browsers may cache a preflight response
+
+	HSTSMaxAge            int    //seconds, sent over TLS only, 0 to disable
+	ContentSecurityPolicy string //for API responses, the swagger UI gets a relaxed policy
 
-	AccessTokenTimeout  int //minutes
-	RefreshTokenTimeout int //hours
+	IdempotencyTTL     int //seconds a stored response is replayed for the same Idempotency-Key
+	IdempotencyLockTTL int //seconds a request holds its key while in flight
+
+	RequireIfMatch bool //reject updates without an If-Match header
+
+	FeatureFlagsRefreshInterval int //seconds between reloads of the feature flags from redis
+
+	AuditBufferSize    int //entries queued for writing, new entries are dropped when it is full
+	AuditBatchSize     int //entries written per insert
+	AuditFlushInterval int //milliseconds between writes of a partial batch
+
+	ListScanLimit int `reload:"true"` //items a v2 list reads from a backend that cannot filter or sort itself
+	CursorTTL     int //seconds a pagination cursor stays valid
+
+	CacheEnabled   bool   `reload:"true"`
+	CacheTTL       int    `reload:"true"` //seconds, for cached routes without their own TTL
+	CacheRouteTTLs string `reload:"true"` //METHOD /route=ttl, comma separated
+
+	AccessLogSampleRate    float64 `reload:"true"` //share of successful requests that are logged, 0..1
+	AccessLogSlowThreshold int     `reload:"true"` //milliseconds
+	AccessLogBody          bool    `reload:"true"`
+
+	MetricsEnabled    bool
+	MetricsAllowedIPs string //IPs or CIDRs, comma separated
+
+	TracingExporter     string //none, otlp, stdout or file
+	TracingOTLPEndpoint string
+	TracingOTLPInsecure bool
+	TracingFilePath     string
+	TracingSampleRatio  float64
 }
 
-func Load() Config {
+// TLSConfig holds the certificate files of one TLS endpoint. The CA bundle
+// verifies the peer, the certificate pair is presented to it.
+type TLSConfig struct {
+	Enabled    bool
+	CAFile     string
+	CertFile   string
+	KeyFile    string
+	ServerName string //overrides the name verified in the peer certificate
+}
+
+// Load builds the config from, in order of precedence, command line flags,
+// environment variables, secret files named by KEY_FILE variables, the
+// YAML or TOML file given by --config or CONFIG_FILE, and the defaults.
+// Flags are the keys in lower case with dashes, e.g. --postgres-host.
+// The result is validated before it is returned.
+func Load(args []string) (Config, error) {
+	l, err := newLoader(args)
+	if err != nil {
+		return Config{}, err
+	}
+
 	c := Config{}
 
-	c.Environment = cast.ToString(getOrReturnDefault("ENVIRONMENT", "develop"))
+	c.Environment = cast.ToString(l.getOrReturnDefault("ENVIRONMENT", "develop"))
 
-	c.PostgresHost = cast.ToString(getOrReturnDefault("POSTGRES_HOST", "localhost"))
-	c.PostgresPort = cast.ToInt(getOrReturnDefault("POSTGRES_PORT", 5432))
-	c.PostgresUser = cast.ToString(getOrReturnDefault("POSTGRES_USER", "postgres"))
-	c.PostgresDatabase = cast.ToString(getOrReturnDefault("POSTGRES_DATABASE", "auth"))
-	c.PostgresPassword = cast.ToString(getOrReturnDefault("POSTGRES_PASSWORD", "mubina2007"))
+	c.PostgresHost = cast.ToString(l.getOrReturnDefault("POSTGRES_HOST", "localhost"))
+	c.PostgresPort = cast.ToInt(l.getOrReturnDefault("POSTGRES_PORT", 5432))
+	c.PostgresUser = cast.ToString(l.getOrReturnDefault("POSTGRES_USER", "postgres"))
+	c.PostgresDatabase = cast.ToString(l.getOrReturnDefault("POSTGRES_DATABASE", "auth"))
+	c.PostgresPassword = cast.ToString(l.getOrReturnDefault("POSTGRES_PASSWORD", defaultPostgresPassword))
 
-	c.RedisHost = cast.ToString(getOrReturnDefault("REDIS_HOST", "localhost"))
-	c.RedisPort = cast.ToInt(getOrReturnDefault("REDIS_PORT", 6379))
+	c.RedisHost = cast.ToString(l.getOrReturnDefault("REDIS_HOST", "localhost"))
+	c.RedisPort = cast.ToInt(l.getOrReturnDefault("REDIS_PORT", 6379))
 
-	c.UserServiceHost = cast.ToString(getOrReturnDefault("USER_SERVICE_HOST", "localhost"))
-	c.UserServicePort = cast.ToInt(getOrReturnDefault("USER_SERVICE_PORT", 8080))
+	c.UserServiceHost = cast.ToString(l.getOrReturnDefault("USER_SERVICE_HOST", "localhost"))
+	c.UserServicePort = cast.ToInt(l.getOrReturnDefault("USER_SERVICE_PORT", 8080))
+	c.UserServiceTLS = loadTLSConfig(l, "USER_SERVICE")
 
-	c.HealthcareServiceHost = cast.ToString(getOrReturnDefault("HEALTHCARE_SERVICE_HOST", "localhost"))
-	c.HealthcareServicePort = cast.ToInt(getOrReturnDefault("HEALTHCARE_SERVICE_PORT", 6060))
+	c.HealthcareServiceHost = cast.ToString(l.getOrReturnDefault("HEALTHCARE_SERVICE_HOST", "localhost"))
+	c.HealthcareServicePort = cast.ToInt(l.getOrReturnDefault("HEALTHCARE_SERVICE_PORT", 6060))
+	c.HealthcareServiceTLS = loadTLSConfig(l, "HEALTHCARE_SERVICE")
 
-	c.BookingServiceHost = cast.ToString(getOrReturnDefault("BOOKING_SERVICE_HOST", "localhost"))
-	c.BookingServicePort = cast.ToInt(getOrReturnDefault("BOOKING_SERVICE_PORT", 9091))
+	c.TLSReloadInterval = cast.ToInt(l.getOrReturnDefault("TLS_RELOAD_INTERVAL", 30))
+	c.ConfigReloadInterval = cast.ToInt(l.getOrReturnDefault("CONFIG_RELOAD_INTERVAL", 10))
 
-	c.CtxTimeout = cast.ToInt(getOrReturnDefault("CTX_TIMEOUT", 7))
+	c.BookingServiceHost = cast.ToString(l.getOrReturnDefault("BOOKING_SERVICE_HOST", "localhost"))
+	c.BookingServicePort = cast.ToInt(l.getOrReturnDefault("BOOKING_SERVICE_PORT", 9091))
 
-	c.AccessTokenTimeout = cast.ToInt(getOrReturnDefault("ACCESS_TOKEN_TIMEOUT", 500))
-	c.RefreshTokenTimeout = cast.ToInt(getOrReturnDefault("REFRESH_TOKEN_TIMEOUT", 3))
+	c.GRPCRetryMaxAttempts = cast.ToInt(l.getOrReturnDefault("GRPC_RETRY_MAX_ATTEMPTS", 3))
+	c.GRPCRetryInitialBackoff = cast.ToInt(l.getOrReturnDefault("GRPC_RETRY_INITIAL_BACKOFF", 100))
+	c.GRPCRetryMaxBackoff = cast.ToInt(l.getOrReturnDefault("GRPC_RETRY_MAX_BACKOFF", 1000))
+	c.GRPCKeepaliveTime = cast.ToInt(l.getOrReturnDefault("GRPC_KEEPALIVE_TIME", 30))
+	c.GRPCKeepaliveTimeout = cast.ToInt(l.getOrReturnDefault("GRPC_KEEPALIVE_TIMEOUT", 10))
+	c.GRPCConnectBackoffMax = cast.ToInt(l.getOrReturnDefault("GRPC_CONNECT_BACKOFF_MAX", 10))
+	c.GRPCBreakerFailures = cast.ToInt(l.getOrReturnDefault("GRPC_BREAKER_FAILURES", 5))
+	c.GRPCBreakerOpenTimeout = cast.ToInt(l.getOrReturnDefault("GRPC_BREAKER_OPEN_TIMEOUT", 30))
 
-	c.LogLevel = cast.ToString(getOrReturnDefault("LOG_LEVEL", "debug"))
-	c.HTTPPort = cast.ToString(getOrReturnDefault("HTTP_PORT", ":7070"))
+	c.CtxTimeout = cast.ToInt(l.getOrReturnDefault("CTX_TIMEOUT", 7))
 
-	c.SignInKey = cast.ToString(getOrReturnDefault("SIGN_IN_KEY", "abc"))
-	c.AccessTokenTimeOut = cast.ToInt(getOrReturnDefault("ACCESS_TOKEN_TIMEOUT", 2000))
-	c.RefreshTokenTimeOut = cast.ToInt(getOrReturnDefault("REFRESH_TOKEN_TIMEOUT", 3000))
+	c.LogLevel = cast.ToString(l.getOrReturnDefault("LOG_LEVEL", "debug"))
+	c.HTTPPort = cast.ToString(l.getOrReturnDefault("HTTP_PORT", ":7070"))
 
-	c.AuthConfigPath = cast.ToString(getOrReturnDefault("AUTH_CONFIG_PATH", "./config/auth.conf"))
+	c.HTTPTLS = loadTLSConfig(l, "HTTP")
+	c.HTTPH2C = cast.ToBool(l.getOrReturnDefault("HTTP_H2C", false))
+	c.HTTPRedirectPort = cast.ToString(l.getOrReturnDefault("HTTP_REDIRECT_PORT", ""))
 
-	c.SendEmailFrom = cast.ToString(getOrReturnDefault("EMAIL_FROM", "mubinayigitaliyeva00@gmail.com"))
-	c.EmailCode = cast.ToString(getOrReturnDefault("EMAIL_CODE", "iocd vnhb lnvx digm"))
-	return c
-}
+	c.ShutdownTimeout = cast.ToInt(l.getOrReturnDefault("SHUTDOWN_TIMEOUT", 15))
+	c.ShutdownDelay = cast.ToInt(l.getOrReturnDefault("SHUTDOWN_DELAY", 3))
+
+	c.HealthCheckTimeout = cast.ToInt(l.getOrReturnDefault("HEALTH_CHECK_TIMEOUT", 2000))
+
+	c.SignInKey = cast.ToString(l.getOrReturnDefault("SIGN_IN_KEY", defaultSignInKey))
+	c.AccessTokenTimeout = cast.ToInt(l.getOrReturnDefault("ACCESS_TOKEN_TIMEOUT", 2000))
+	c.RefreshTokenTimeout = cast.ToInt(l.getOrReturnDefault("REFRESH_TOKEN_TIMEOUT", 10800))
+
+	c.AuthConfigPath = cast.ToString(l.getOrReturnDefault("AUTH_CONFIG_PATH", "./config/auth.conf"))
+
+	c.SendEmailFrom = cast.ToString(l.getOrReturnDefault("EMAIL_FROM", "no-reply@localhost"))
+	c.EmailCode = cast.ToString(l.getOrReturnDefault("EMAIL_CODE", ""))
+
+	c.RateLimitEnabled = cast.ToBool(l.getOrReturnDefault("RATE_LIMIT_ENABLED", true))
+	c.RateLimitDefault = cast.ToString(l.getOrReturnDefault("RATE_LIMIT_DEFAULT", "300/1m"))
+	c.RateLimitRoles = cast.ToString(l.getOrReturnDefault("RATE_LIMIT_ROLES", "unauthorized=60/1m,user=120/1m,doctor=120/1m,admin=600/1m,superadmin=1200/1m"))
+	c.RateLimitRoutes = cast.ToString(l.getOrReturnDefault("RATE_LIMIT_ROUTES", "POST /v1/register=5/1m,POST /v1/doctor/register=5/1m,POST /v1/login=10/1m,POST /v1/doctor/login=10/1m,POST /v1/auth/login=10/1m"))
+	c.RateLimitAPIKeys = cast.ToString(l.getOrReturnDefault("RATE_LIMIT_API_KEYS", ""))
+
+	c.TrustedProxies = cast.ToString(l.getOrReturnDefault("TRUSTED_PROXIES", ""))
+
+	c.CORSAllowedOrigins = cast.ToString(l.getOrReturnDefault("CORS_ALLOWED_ORIGINS", ""))
+	c.CORSAllowedMethods = cast.ToString(l.getOrReturnDefault("CORS_ALLOWED_METHODS", "GET,POST,PUT,PATCH,DELETE,OPTIONS"))
+	c.CORSAllowedHeaders = cast.ToString(l.getOrReturnDefault("CORS_ALLOWED_HEADERS", "Authorization,Content-Type,Accept,Accept-Language,X-Request-ID,X-API-Key,If-Match,If-None-Match,Idempotency-Key"))
+	c.CORSExposedHeaders = cast.ToString(l.getOrReturnDefault("CORS_EXPOSED_HEADERS", "X-Request-ID,ETag,Link,X-Cache,Retry-After,X-RateLimit-Limit,X-RateLimit-Remaining,X-RateLimit-Reset"))
+	c.CORSAllowCredentials = cast.ToBool(l.getOrReturnDefault("CORS_ALLOW_CREDENTIALS", false))
+	c.CORSMaxAge = cast.ToInt(l.getOrReturnDefault("CORS_MAX_AGE", 600))
+
+	c.HSTSMaxAge = cast.ToInt(l.getOrReturnDefault("HSTS_MAX_AGE", 31536000))
+	c.ContentSecurityPolicy = cast.ToString(l.getOrReturnDefault("CONTENT_SECURITY_POLICY", "default-src 'none'; frame-ancestors 'none'"))
+
+	c.IdempotencyTTL = cast.ToInt(l.getOrReturnDefault("IDEMPOTENCY_TTL", 86400))
+	c.IdempotencyLockTTL = cast.ToInt(l.getOrReturnDefault("IDEMPOTENCY_LOCK_TTL", 30))
 
-func getOrReturnDefault(key string, defaultValue interface{}) interface{} {
-	_, exists := os.LookupEnv(key)
-	if exists {
-		return os.Getenv(key)
+	c.RequireIfMatch = cast.ToBool(l.getOrReturnDefault("REQUIRE_IF_MATCH", false))
+
+	c.FeatureFlagsRefreshInterval = cast.ToInt(l.getOrReturnDefault("FEATURE_FLAGS_REFRESH_INTERVAL", 5))
+
+	c.AuditBufferSize = cast.ToInt(l.getOrReturnDefault("AUDIT_BUFFER_SIZE", 10000))
+	c.AuditBatchSize = cast.ToInt(l.getOrReturnDefault("AUDIT_BATCH_SIZE", 100))
+	c.AuditFlushInterval = cast.ToInt(l.getOrReturnDefault("AUDIT_FLUSH_INTERVAL", 1000))
+
+	c.ListScanLimit = cast.ToInt(l.getOrReturnDefault("LIST_SCAN_LIMIT", 5000))
+	c.CursorTTL = cast.ToInt(l.getOrReturnDefault("CURSOR_TTL", 3600))
+
+	c.CacheEnabled = cast.ToBool(l.getOrReturnDefault("CACHE_ENABLED", true))
+	c.CacheTTL = cast.ToInt(l.getOrReturnDefault("CACHE_TTL", 60))
+	c.CacheRouteTTLs = cast.ToString(l.getOrReturnDefault("CACHE_ROUTE_TTLS", "GET /v1/departments/:page/:limit=5m,GET /v1/department/:id=5m,GET /v1/specializations/:page/:limit=5m,GET /v1/specializations/:page/:limit/:department_id=5m,GET /v1/specprices/:page/:limit=2m,GET /v1/doctors/:page/:limit=1m"))
+
+	c.AccessLogSampleRate = cast.ToFloat64(l.getOrReturnDefault("ACCESS_LOG_SAMPLE_RATE", 1))
+	c.AccessLogSlowThreshold = cast.ToInt(l.getOrReturnDefault("ACCESS_LOG_SLOW_THRESHOLD", 1000))
+	c.AccessLogBody = cast.ToBool(l.getOrReturnDefault("ACCESS_LOG_BODY", false))
+
+	c.MetricsEnabled = cast.ToBool(l.getOrReturnDefault("METRICS_ENABLED", true))
+	c.MetricsAllowedIPs = cast.ToString(l.getOrReturnDefault("METRICS_ALLOWED_IPS", "127.0.0.1,::1,10.0.0.0/8,172.16.0.0/12,192.168.0.0/16"))
+
+	c.TracingExporter = cast.ToString(l.getOrReturnDefault("TRACING_EXPORTER", "none"))
+	c.TracingOTLPEndpoint = cast.ToString(l.getOrReturnDefault("TRACING_OTLP_ENDPOINT", "localhost:4317"))
+	c.TracingOTLPInsecure = cast.ToBool(l.getOrReturnDefault("TRACING_OTLP_INSECURE", true))
+	c.TracingFilePath = cast.ToString(l.getOrReturnDefault("TRACING_FILE_PATH", "./traces.json"))
+	c.TracingSampleRatio = cast.ToFloat64(l.getOrReturnDefault("TRACING_SAMPLE_RATIO", 1))
+
+	if err := l.err(); err != nil {
+		return Config{}, err
 	}
 
-	return defaultValue
+	return c, c.Validate()
+}
+
+func loadTLSConfig(l *loader, prefix string) TLSConfig {
+	return TLSConfig{
+		Enabled:    cast.ToBool(l.getOrReturnDefault(prefix+"_TLS_ENABLED", false)),
+		CAFile:     cast.ToString(l.getOrReturnDefault(prefix+"_TLS_CA_FILE", "")),
+		CertFile:   cast.ToString(l.getOrReturnDefault(prefix+"_TLS_CERT_FILE", "")),
+		KeyFile:    cast.ToString(l.getOrReturnDefault(prefix+"_TLS_KEY_FILE", "")),
+		ServerName: cast.ToString(l.getOrReturnDefault(prefix+"_TLS_SERVER_NAME", "")),
+	}
 }
@@ -0,0 +1,237 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// isolate unsets the variables a test reads, so that the environment the
+// tests run in does not leak into them
+func isolate(t *testing.T, keys ...string) {
+	t.Helper()
+
+	for _, key := range append(keys, "CONFIG_FILE") {
+		t.Setenv(key, "")
+		os.Unsetenv(key)
+	}
+}
+
+func writeConfig(t *testing.T, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	yamlFile := "postgres:\n  host: file-host\n  port: 5433\nlog_level: warn\ncors:\n  allowed_origins:\n    - https://a.example.com\n    - https://b.example.com\n"
+	secretFile := "s3cret\n"
+
+	tests := []struct {
+		name  string
+		file  string
+		env   map[string]string
+		args  []string
+		check func(t *testing.T, c Config)
+	}{
+		{
+			name: "defaults",
+			check: func(t *testing.T, c Config) {
+				if c.PostgresHost != "localhost" || c.PostgresPort != 5432 || c.LogLevel != "debug" {
+					t.Errorf("defaults = %s:%d %s", c.PostgresHost, c.PostgresPort, c.LogLevel)
+				}
+			},
+		},
+		{
+			name: "file over defaults",
+			file: yamlFile,
+			check: func(t *testing.T, c Config) {
+				if c.PostgresHost != "file-host" || c.PostgresPort != 5433 || c.LogLevel != "warn" {
+					t.Errorf("from the file = %s:%d %s", c.PostgresHost, c.PostgresPort, c.LogLevel)
+				}
+				if c.CORSAllowedOrigins != "https://a.example.com,https://b.example.com" {
+					t.Errorf("list from the file = %q", c.CORSAllowedOrigins)
+				}
+			},
+		},
+		{
+			name: "env over file",
+			file: yamlFile,
+			env:  map[string]string{"POSTGRES_HOST": "env-host"},
+			check: func(t *testing.T, c Config) {
+				if c.PostgresHost != "env-host" || c.PostgresPort != 5433 {
+					t.Errorf("env over file = %s:%d", c.PostgresHost, c.PostgresPort)
+				}
+			},
+		},
+		{
+			name: "secret file over config file",
+			file: "postgres:\n  password: from-file\n",
+			env:  map[string]string{"POSTGRES_PASSWORD_FILE": "secret"},
+			check: func(t *testing.T, c Config) {
+				if c.PostgresPassword != "s3cret" {
+					t.Errorf("POSTGRES_PASSWORD_FILE = %q, want the trimmed file content", c.PostgresPassword)
+				}
+			},
+		},
+		{
+			name: "flags over env",
+			file: yamlFile,
+			env:  map[string]string{"POSTGRES_HOST": "env-host", "POSTGRES_PASSWORD_FILE": "secret"},
+			args: []string{"--postgres-host=flag-host", "--postgres-port", "6000", "--postgres-password", "flag-pass"},
+			check: func(t *testing.T, c Config) {
+				if c.PostgresHost != "flag-host" || c.PostgresPort != 6000 || c.PostgresPassword != "flag-pass" {
+					t.Errorf("flags = %s:%d %q", c.PostgresHost, c.PostgresPort, c.PostgresPassword)
+				}
+			},
+		},
+		{
+			name: "bare flag is true",
+			args: []string{"--require-if-match"},
+			check: func(t *testing.T, c Config) {
+				if !c.RequireIfMatch {
+					t.Error("--require-if-match did not set RequireIfMatch")
+				}
+			},
+		},
+		{
+			name: "toml file",
+			file: "toml",
+			check: func(t *testing.T, c Config) {
+				if c.RedisHost != "toml-host" || c.RedisPort != 6380 {
+					t.Errorf("from the toml file = %s:%d", c.RedisHost, c.RedisPort)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			isolate(t, "POSTGRES_HOST", "POSTGRES_PORT", "POSTGRES_PASSWORD", "POSTGRES_PASSWORD_FILE",
+				"LOG_LEVEL", "CORS_ALLOWED_ORIGINS", "REQUIRE_IF_MATCH", "REDIS_HOST", "REDIS_PORT")
+
+			args := tt.args
+			switch tt.file {
+			case "":
+			case "toml":
+				args = append(args, "--config", writeConfig(t, "config.toml", "[redis]\nhost = \"toml-host\"\nport = 6380\n"))
+			default:
+				t.Setenv("CONFIG_FILE", writeConfig(t, "config.yaml", tt.file))
+			}
+			for key, value := range tt.env {
+				if value == "secret" {
+					value = writeConfig(t, "secret", secretFile)
+				}
+				t.Setenv(key, value)
+			}
+
+			c, err := Load(args)
+			if err != nil {
+				t.Fatalf("Load() failed: %v", err)
+			}
+			tt.check(t, c)
+		})
+	}
+}
+
+func TestLoadRejects(t *testing.T) {
+	tests := []struct {
+		name     string
+		file     string
+		fileName string
+		env      map[string]string
+		args     []string
+		wantErr  string
+	}{
+		{
+			name:    "value and secret file",
+			env:     map[string]string{"POSTGRES_PASSWORD": "pass", "POSTGRES_PASSWORD_FILE": "secret"},
+			wantErr: "both POSTGRES_PASSWORD and POSTGRES_PASSWORD_FILE are set",
+		},
+		{
+			name:    "missing secret file",
+			env:     map[string]string{"POSTGRES_PASSWORD_FILE": "/nonexistent/secret"},
+			wantErr: "cannot read POSTGRES_PASSWORD_FILE",
+		},
+		{
+			name:    "unknown flag",
+			args:    []string{"--postgres-hots=db"},
+			wantErr: "unknown flag --postgres-hots",
+		},
+		{
+			name:    "positional argument",
+			args:    []string{"serve"},
+			wantErr: `unexpected argument "serve"`,
+		},
+		{
+			name:    "unknown file key",
+			file:    "postgres:\n  hots: db\n",
+			wantErr: "unknown config file key postgres_hots",
+		},
+		{
+			name:     "unsupported file type",
+			file:     `{"postgres_host": "db"}`,
+			fileName: "config.json",
+			wantErr:  "should be .yaml, .yml or .toml",
+		},
+		{
+			name:    "invalid value",
+			args:    []string{"--log-level=fatal"},
+			wantErr: `LOG_LEVEL "fatal" is unknown`,
+		},
+		{
+			name:    "development secrets in production",
+			args:    []string{"--environment=production"},
+			wantErr: "SIGN_IN_KEY should be set",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			isolate(t, "POSTGRES_PASSWORD", "POSTGRES_PASSWORD_FILE", "ENVIRONMENT", "LOG_LEVEL", "SIGN_IN_KEY")
+
+			if tt.file != "" {
+				fileName := tt.fileName
+				if fileName == "" {
+					fileName = "config.yaml"
+				}
+				t.Setenv("CONFIG_FILE", writeConfig(t, fileName, tt.file))
+			}
+			for key, value := range tt.env {
+				if value == "secret" {
+					value = writeConfig(t, "secret", "s3cret")
+				}
+				t.Setenv(key, value)
+			}
+
+			_, err := Load(tt.args)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Load() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRedacted(t *testing.T) {
+	c := Config{PostgresHost: "db", PostgresPassword: "pass", SignInKey: "", HTTPTLS: TLSConfig{CertFile: "cert.pem"}}
+
+	redacted := c.Redacted()
+	if redacted["PostgresPassword"] != "[REDACTED]" {
+		t.Errorf("PostgresPassword = %v, want it masked", redacted["PostgresPassword"])
+	}
+	if redacted["SignInKey"] != "" {
+		t.Errorf("empty SignInKey = %v, want it empty", redacted["SignInKey"])
+	}
+	if redacted["PostgresHost"] != "db" {
+		t.Errorf("PostgresHost = %v, want db", redacted["PostgresHost"])
+	}
+	if tls, _ := redacted["HTTPTLS"].(map[string]interface{}); tls["CertFile"] != "cert.pem" {
+		t.Errorf("HTTPTLS = %v, want the nested fields", redacted["HTTPTLS"])
+	}
+}
@@ -0,0 +1,186 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+	"github.com/spf13/cast"
+	"gopkg.in/yaml.v3"
+)
+
+// loader looks keys up in the config sources, keeping track of the keys
+// that were read so that unknown flags and file entries are reported
+type loader struct {
+	flags map[string]string
+	file  map[string]interface{}
+	used  map[string]bool
+	errs  []error
+}
+
+func newLoader(args []string) (*loader, error) {
+	l := &loader{
+		file: make(map[string]interface{}),
+		used: make(map[string]bool),
+	}
+
+	flags, err := parseFlags(args)
+	if err != nil {
+		return nil, err
+	}
+	l.flags = flags
+
+	path := configPath(flags)
+	delete(l.flags, "CONFIG")
+
+	if path != "" {
+		if err := l.readFile(path); err != nil {
+			return nil, err
+		}
+	}
+
+	return l, nil
+}
+
+// configPath returns the config file given by --config or CONFIG_FILE, if any
+func configPath(flags map[string]string) string {
+	if path, ok := flags["CONFIG"]; ok {
+		return path
+	}
+
+	return os.Getenv("CONFIG_FILE")
+}
+
+func (l *loader) getOrReturnDefault(key string, defaultValue interface{}) interface{} {
+	l.used[key] = true
+
+	if value, ok := l.flags[key]; ok {
+		return value
+	}
+
+	value, exists := os.LookupEnv(key)
+	secretFile, secretExists := os.LookupEnv(key + "_FILE")
+	switch {
+	case exists && secretExists:
+		l.errs = append(l.errs, fmt.Errorf("both %s and %s_FILE are set, use one of them", key, key))
+	case exists:
+		return value
+	case secretExists:
+		secret, err := os.ReadFile(secretFile)
+		if err != nil {
+			l.errs = append(l.errs, fmt.Errorf("cannot read %s_FILE: %w", key, err))
+			break
+		}
+		return strings.TrimSpace(string(secret))
+	}
+
+	if value, ok := l.file[key]; ok {
+		return value
+	}
+
+	return defaultValue
+}
+
+// err reports the lookup errors and the flags or file keys that match no setting
+func (l *loader) err() error {
+	errs := l.errs
+	for _, key := range sortedKeys(l.flags) {
+		if !l.used[key] {
+			errs = append(errs, fmt.Errorf("unknown flag --%s", strings.ReplaceAll(strings.ToLower(key), "_", "-")))
+		}
+	}
+	for _, key := range sortedKeys(l.file) {
+		if !l.used[key] {
+			errs = append(errs, fmt.Errorf("unknown config file key %s", strings.ToLower(key)))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// readFile loads a YAML or TOML file. Nested sections are flattened, so
+// postgres: {host: db} and postgres_host: db both set POSTGRES_HOST.
+func (l *loader) readFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("cannot read config file: %w", err)
+	}
+
+	values := make(map[string]interface{})
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &values)
+	case ".toml":
+		err = toml.Unmarshal(data, &values)
+	default:
+		return fmt.Errorf("config file %s should be .yaml, .yml or .toml", path)
+	}
+	if err != nil {
+		return fmt.Errorf("cannot parse config file %s: %w", path, err)
+	}
+
+	flatten("", values, l.file)
+	return nil
+}
+
+func flatten(prefix string, values map[string]interface{}, into map[string]interface{}) {
+	for key, value := range values {
+		key = strings.ToUpper(strings.ReplaceAll(key, "-", "_"))
+		if prefix != "" {
+			key = prefix + "_" + key
+		}
+
+		switch nested := value.(type) {
+		case map[string]interface{}:
+			flatten(key, nested, into)
+		case []interface{}:
+			// lists are accepted wherever a comma separated value is expected
+			into[key] = strings.Join(cast.ToStringSlice(nested), ",")
+		default:
+			into[key] = value
+		}
+	}
+}
+
+// parseFlags accepts --key=value, --key value and bare --key for true
+func parseFlags(args []string) (map[string]string, error) {
+	flags := make(map[string]string)
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if !strings.HasPrefix(arg, "-") {
+			return nil, fmt.Errorf("unexpected argument %q, flags look like --key=value", arg)
+		}
+
+		name, value, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		if name == "" {
+			return nil, fmt.Errorf("invalid flag %q", arg)
+		}
+		if !hasValue {
+			if i+1 < len(args) && !strings.HasPrefix(args[i+1], "-") {
+				i++
+				value = args[i]
+			} else {
+				value = "true"
+			}
+		}
+
+		flags[strings.ToUpper(strings.ReplaceAll(name, "-", "_"))] = value
+	}
+
+	return flags, nil
+}
+
+func sortedKeys[V any](values map[string]V) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
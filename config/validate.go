@@ -0,0 +1,124 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"myproject/admin-api-gateway/pkg/logger"
+	"myproject/admin-api-gateway/pkg/redact"
+	"net"
+	"reflect"
+	"strings"
+)
+
+// IsProduction reports whether the gateway runs in production
+func (c Config) IsProduction() bool {
+	switch strings.ToLower(c.Environment) {
+	case "production", "prod":
+		return true
+	}
+
+	return false
+}
+
+// TrustedProxyList returns the IPs and CIDRs of TRUSTED_PROXIES
+func (c Config) TrustedProxyList() []string {
+	var proxies []string
+	for _, proxy := range strings.Split(c.TrustedProxies, ",") {
+		if proxy = strings.TrimSpace(proxy); proxy != "" {
+			proxies = append(proxies, proxy)
+		}
+	}
+
+	return proxies
+}
+
+// Validate checks the values that would otherwise fail at runtime and
+// refuses development secrets in production
+func (c Config) Validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...interface{}) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+
+	for name, port := range map[string]int{
+		"POSTGRES_PORT":           c.PostgresPort,
+		"REDIS_PORT":              c.RedisPort,
+		"USER_SERVICE_PORT":       c.UserServicePort,
+		"HEALTHCARE_SERVICE_PORT": c.HealthcareServicePort,
+	} {
+		check(port > 0 && port < 65536, "%s should be a valid port, got %d", name, port)
+	}
+	check(c.HTTPPort != "", "HTTP_PORT is required")
+
+	check(logger.ValidLevel(strings.ToLower(c.LogLevel)), "LOG_LEVEL %q is unknown", c.LogLevel)
+
+	check(c.CtxTimeout > 0, "CTX_TIMEOUT should be positive")
+	check(c.AccessTokenTimeout > 0, "ACCESS_TOKEN_TIMEOUT should be positive")
+	check(c.RefreshTokenTimeout > 0, "REFRESH_TOKEN_TIMEOUT should be positive")
+	check(c.ShutdownTimeout > 0, "SHUTDOWN_TIMEOUT should be positive")
+	check(c.HealthCheckTimeout > 0, "HEALTH_CHECK_TIMEOUT should be positive")
+	check(c.TLSReloadInterval > 0, "TLS_RELOAD_INTERVAL should be positive")
+	check(c.AuditBufferSize > 0, "AUDIT_BUFFER_SIZE should be positive")
+	check(c.AuditBatchSize > 0, "AUDIT_BATCH_SIZE should be positive")
+	check(c.AuditFlushInterval > 0, "AUDIT_FLUSH_INTERVAL should be positive")
+	check(c.ListScanLimit > 0, "LIST_SCAN_LIMIT should be positive")
+	check(c.CursorTTL > 0, "CURSOR_TTL should be positive")
+	check(c.FeatureFlagsRefreshInterval > 0, "FEATURE_FLAGS_REFRESH_INTERVAL should be positive")
+	check(c.ConfigReloadInterval >= 0, "CONFIG_RELOAD_INTERVAL should not be negative")
+	check(c.AccessLogSampleRate >= 0 && c.AccessLogSampleRate <= 1, "ACCESS_LOG_SAMPLE_RATE should be between 0 and 1")
+	check(c.TracingSampleRatio >= 0 && c.TracingSampleRatio <= 1, "TRACING_SAMPLE_RATIO should be between 0 and 1")
+
+	switch c.TracingExporter {
+	case "none", "otlp", "stdout", "file":
+	default:
+		check(false, "TRACING_EXPORTER should be one of none, otlp, stdout or file, got %q", c.TracingExporter)
+	}
+
+	check(!c.HTTPTLS.Enabled || (c.HTTPTLS.CertFile != "" && c.HTTPTLS.KeyFile != ""), "HTTP_TLS_CERT_FILE and HTTP_TLS_KEY_FILE are required when HTTP_TLS_ENABLED is set")
+	for name, tls := range map[string]TLSConfig{"USER_SERVICE": c.UserServiceTLS, "HEALTHCARE_SERVICE": c.HealthcareServiceTLS} {
+		check((tls.CertFile == "") == (tls.KeyFile == ""), "%s_TLS_CERT_FILE and %s_TLS_KEY_FILE should be set together", name, name)
+	}
+
+	for _, proxy := range c.TrustedProxyList() {
+		_, _, cidrErr := net.ParseCIDR(proxy)
+		check(cidrErr == nil || net.ParseIP(proxy) != nil, "TRUSTED_PROXIES should hold IPs or CIDRs, got %q", proxy)
+	}
+
+	if c.IsProduction() {
+		check(c.SignInKey != defaultSignInKey && len(c.SignInKey) >= 32, "SIGN_IN_KEY should be set to a random value of at least 32 characters in production")
+		check(c.PostgresPassword != "" && c.PostgresPassword != defaultPostgresPassword, "POSTGRES_PASSWORD should not use the default value in production")
+		check(c.EmailCode != "", "EMAIL_CODE is required in production")
+	}
+
+	return errors.Join(errs...)
+}
+
+// Redacted returns the config with the fields tagged as secret masked,
+// suitable for logging and for the config endpoint
+func (c Config) Redacted() map[string]interface{} {
+	return redactStruct(reflect.ValueOf(c))
+}
+
+func redactStruct(value reflect.Value) map[string]interface{} {
+	fields := make(map[string]interface{}, value.NumField())
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+
+		switch {
+		case field.Tag.Get("secret") == "true":
+			if value.Field(i).IsZero() {
+				fields[field.Name] = ""
+			} else {
+				fields[field.Name] = redact.Mask
+			}
+		case field.Type.Kind() == reflect.Struct:
+			fields[field.Name] = redactStruct(value.Field(i))
+		default:
+			fields[field.Name] = value.Field(i).Interface()
+		}
+	}
+
+	return fields
+}
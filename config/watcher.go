@@ -0,0 +1,155 @@
+package config
+
+import (
+	"context"
+	"myproject/admin-api-gateway/pkg/logger"
+	"os"
+	"os/signal"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+// Watcher holds the running config and reloads it on SIGHUP or when the
+// config file changes. Only the fields tagged reload:"true" are applied,
+// changes of the other fields are logged and wait for a restart.
+type Watcher struct {
+	args    []string
+	path    string
+	log     logger.Logger
+	current atomic.Pointer[Config]
+
+	mu          sync.Mutex // serializes reloads and guards subscribers
+	subscribers []func(prev, next Config)
+}
+
+// NewWatcher starts from cfg, reloads read the sources again with the
+// command line args the process was started with
+func NewWatcher(cfg Config, args []string, log logger.Logger) *Watcher {
+	w := &Watcher{
+		args: args,
+		log:  log,
+	}
+	if flags, err := parseFlags(args); err == nil {
+		w.path = configPath(flags)
+	}
+	w.current.Store(&cfg)
+
+	return w
+}
+
+// Current returns the config with the latest runtime changes applied
+func (w *Watcher) Current() Config {
+	return *w.current.Load()
+}
+
+// Subscribe registers fn to be called after every reload that changed a
+// runtime field. Subscribers run one at a time in the order they were added.
+func (w *Watcher) Subscribe(fn func(prev, next Config)) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.subscribers = append(w.subscribers, fn)
+}
+
+// Reload loads and validates the config again and applies its runtime
+// fields. An invalid config is rejected as a whole.
+func (w *Watcher) Reload() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	loaded, err := Load(w.args)
+	if err != nil {
+		return err
+	}
+
+	prev := w.Current()
+	next, applied, pending := merge(prev, loaded)
+	if len(pending) > 0 {
+		w.log.Warn("config changes require a restart", logger.String("fields", strings.Join(pending, ",")))
+	}
+	if len(applied) == 0 {
+		return nil
+	}
+
+	w.current.Store(&next)
+	for _, fn := range w.subscribers {
+		fn(prev, next)
+	}
+	w.log.Info("config reloaded", logger.String("fields", strings.Join(applied, ",")))
+
+	return nil
+}
+
+// Run reloads on SIGHUP and, if a config file is used, whenever its
+// modification time changes. It blocks until ctx is cancelled.
+func (w *Watcher) Run(ctx context.Context, interval time.Duration) {
+	hangup := make(chan os.Signal, 1)
+	signal.Notify(hangup, syscall.SIGHUP)
+	defer signal.Stop(hangup)
+
+	var tick <-chan time.Time
+	if w.path != "" && interval > 0 {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+	modTime := w.modTime()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hangup:
+			w.log.Info("SIGHUP received, reloading config")
+		case <-tick:
+			current := w.modTime()
+			if current.Equal(modTime) {
+				continue
+			}
+			modTime = current
+			w.log.Info("config file changed, reloading config", logger.String("path", w.path))
+		}
+
+		if err := w.Reload(); err != nil {
+			w.log.Error("cannot reload config, keeping the running one", logger.Error(err))
+		}
+	}
+}
+
+func (w *Watcher) modTime() time.Time {
+	info, err := os.Stat(w.path)
+	if err != nil {
+		return time.Time{}
+	}
+
+	return info.ModTime()
+}
+
+// merge copies the runtime fields of loaded onto prev and returns the names
+// of the fields it applied and of the changed fields it had to leave alone
+func merge(prev, loaded Config) (Config, []string, []string) {
+	next := prev
+	value := reflect.ValueOf(&next).Elem()
+	from := reflect.ValueOf(loaded)
+
+	var applied, pending []string
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		if reflect.DeepEqual(value.Field(i).Interface(), from.Field(i).Interface()) {
+			continue
+		}
+
+		if field.Tag.Get("reload") != "true" {
+			pending = append(pending, field.Name)
+			continue
+		}
+		value.Field(i).Set(from.Field(i))
+		applied = append(applied, field.Name)
+	}
+
+	return next, applied, pending
+}
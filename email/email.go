@@ -3,6 +3,9 @@ package email
 import (
 	"html/template"
 	"log"
+	"mime"
+	"myproject/admin-api-gateway/pkg/i18n"
+	"myproject/admin-api-gateway/pkg/metrics"
 	"net/smtp"
 	"os"
 	"strings"
@@ -14,6 +17,8 @@ type EmailPayload struct {
 	Password string
 	Code     string
 	Message  string
+	// Language of the email, English when empty
+	Language string
 }
 
 func SendVerificationCode(params EmailPayload) (string, error) {
@@ -23,7 +28,14 @@ func SendVerificationCode(params EmailPayload) (string, error) {
 		return "", err
 	}
 
-	temp, err := template.New("email").Parse(string(htmlFile))
+	if params.Language == "" {
+		params.Language = i18n.Default
+	}
+	translate := func(message string) string {
+		return i18n.T(params.Language, message)
+	}
+
+	temp, err := template.New("email").Funcs(template.FuncMap{"t": translate}).Parse(string(htmlFile))
 	if err != nil {
 		log.Println("Cannot parse file", err)
 		return "", err
@@ -38,7 +50,7 @@ func SendVerificationCode(params EmailPayload) (string, error) {
 
 	message := "From: " + params.From + "\n" +
 		"To: " + params.To + "\n" +
-		"Subject: Super-clinic app\n" +
+		"Subject: " + mime.QEncoding.Encode("utf-8", translate("Super-clinic app")) + "\n" +
 		"MIME-Version: 1.0\n" +
 		"Content-type: text/html; charset=\"UTF-8\"\n" +
 		"\n" +
@@ -50,9 +62,11 @@ func SendVerificationCode(params EmailPayload) (string, error) {
 	)
 
 	if err != nil {
+		metrics.EmailsSent.WithLabelValues("failure").Inc()
 		log.Println("cannot send an email verification", err)
 		return "", err
 	}
+	metrics.EmailsSent.WithLabelValues("success").Inc()
 
 	return "a verification code was sent to your email, please check it", nil
 }
@@ -0,0 +1 @@
+DELETE FROM casbin_rule WHERE ptype = 'p' AND v1 = '/v1/profile/language';
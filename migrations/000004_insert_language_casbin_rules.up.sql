@@ -0,0 +1,5 @@
+-- Let users and doctors read and save their language, admins inherit it from users --
+INSERT INTO casbin_rule (ptype, v0, v1, v2) VALUES ('p', 'user', '/v1/profile/language', 'GET');
+INSERT INTO casbin_rule (ptype, v0, v1, v2) VALUES ('p', 'user', '/v1/profile/language', 'PUT');
+INSERT INTO casbin_rule (ptype, v0, v1, v2) VALUES ('p', 'doctor', '/v1/profile/language', 'GET');
+INSERT INTO casbin_rule (ptype, v0, v1, v2) VALUES ('p', 'doctor', '/v1/profile/language', 'PUT');
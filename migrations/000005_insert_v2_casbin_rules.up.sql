@@ -0,0 +1,7 @@
+-- Allow the v2 lists to the roles allowed the matching v1 lists, admins inherit the rules of users --
+INSERT INTO casbin_rule (ptype, v0, v1, v2) VALUES ('p', 'user', '/v2/doctors', 'GET');
+INSERT INTO casbin_rule (ptype, v0, v1, v2) VALUES ('p', 'user', '/v2/departments', 'GET');
+INSERT INTO casbin_rule (ptype, v0, v1, v2) VALUES ('p', 'doctor', '/v2/doctors', 'GET');
+INSERT INTO casbin_rule (ptype, v0, v1, v2) VALUES ('p', 'doctor', '/v2/departments', 'GET');
+INSERT INTO casbin_rule (ptype, v0, v1, v2) VALUES ('p', 'admin', '/v2/users', 'GET');
+INSERT INTO casbin_rule (ptype, v0, v1, v2) VALUES ('p', 'admin', '/v2/admins', 'GET');
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS admins_created_at_id_idx;
+
+ALTER TABLE admins DROP COLUMN IF EXISTS created_at;
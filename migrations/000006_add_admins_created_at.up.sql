@@ -0,0 +1,3 @@
+ALTER TABLE admins ADD COLUMN IF NOT EXISTS created_at TIMESTAMPTZ NOT NULL DEFAULT now();
+
+CREATE INDEX IF NOT EXISTS admins_created_at_id_idx ON admins (created_at, id);
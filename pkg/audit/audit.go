@@ -0,0 +1,207 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"myproject/admin-api-gateway/api/models"
+	"myproject/admin-api-gateway/pkg/logger"
+	"myproject/admin-api-gateway/pkg/metrics"
+	"myproject/admin-api-gateway/pkg/redact"
+	"myproject/admin-api-gateway/storage/postgresrepo"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// Recorder writes audit entries to the store in batches from a background
+// goroutine, so that recording never waits for the database. When the
+// buffer is full new entries are dropped and counted rather than blocking
+// the request.
+type Recorder struct {
+	store         postgresrepo.AuditStorageI
+	log           logger.Logger
+	batchSize     int
+	flushInterval time.Duration
+
+	mu      sync.RWMutex // guards closed against Record racing Close
+	closed  bool
+	entries chan *models.AuditEntry
+	done    chan struct{}
+}
+
+func NewRecorder(store postgresrepo.AuditStorageI, log logger.Logger, bufferSize, batchSize int, flushInterval time.Duration) *Recorder {
+	r := &Recorder{
+		store:         store,
+		log:           log,
+		batchSize:     batchSize,
+		flushInterval: flushInterval,
+		entries:       make(chan *models.AuditEntry, bufferSize),
+		done:          make(chan struct{}),
+	}
+	go r.run()
+
+	return r
+}
+
+// Record queues the entry, computing its diff first
+func (r *Recorder) Record(entry *models.AuditEntry) {
+	entry.Diff = Diff(entry.Before, entry.After)
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.closed {
+		metrics.AuditEntries.WithLabelValues("dropped").Inc()
+		return
+	}
+
+	select {
+	case r.entries <- entry:
+	default:
+		metrics.AuditEntries.WithLabelValues("dropped").Inc()
+		r.log.Warn("audit buffer is full, entry dropped",
+			logger.String("action", entry.Action),
+			logger.String("resource_type", entry.ResourceType),
+			logger.String("request_id", entry.RequestID))
+	}
+}
+
+// Close stops accepting entries and waits until the queued ones are written
+func (r *Recorder) Close() error {
+	r.mu.Lock()
+	if !r.closed {
+		r.closed = true
+		close(r.entries)
+	}
+	r.mu.Unlock()
+
+	<-r.done
+	return nil
+}
+
+func (r *Recorder) run() {
+	defer close(r.done)
+
+	ticker := time.NewTicker(r.flushInterval)
+	defer ticker.Stop()
+
+	batch := make([]*models.AuditEntry, 0, r.batchSize)
+	for {
+		select {
+		case entry, ok := <-r.entries:
+			if !ok {
+				r.flush(batch)
+				return
+			}
+			batch = append(batch, entry)
+			if len(batch) < r.batchSize {
+				continue
+			}
+		case <-ticker.C:
+		}
+
+		r.flush(batch)
+		batch = batch[:0]
+	}
+}
+
+func (r *Recorder) flush(batch []*models.AuditEntry) {
+	if len(batch) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := r.store.Insert(ctx, batch); err != nil {
+		metrics.AuditEntries.WithLabelValues("failed").Add(float64(len(batch)))
+		r.log.Error("cannot write audit entries", logger.Int("count", len(batch)), logger.Error(err))
+		return
+	}
+	metrics.AuditEntries.WithLabelValues("written").Add(float64(len(batch)))
+}
+
+type entryKey struct{}
+
+// WithEntry returns a copy of ctx carrying the audit entry of the request
+func WithEntry(ctx context.Context, entry *models.AuditEntry) context.Context {
+	return context.WithValue(ctx, entryKey{}, entry)
+}
+
+// FromContext returns the audit entry of the request, nil when the request is not audited
+func FromContext(ctx context.Context) *models.AuditEntry {
+	entry, _ := ctx.Value(entryKey{}).(*models.AuditEntry)
+	return entry
+}
+
+// SetResource names the resource the request acts on
+func SetResource(ctx context.Context, resourceType, resourceID string) {
+	if entry := FromContext(ctx); entry != nil {
+		entry.ResourceType = resourceType
+		entry.ResourceID = resourceID
+	}
+}
+
+// SetAction replaces the action derived from the HTTP method
+func SetAction(ctx context.Context, action string) {
+	if entry := FromContext(ctx); entry != nil {
+		entry.Action = action
+	}
+}
+
+// SetBefore stores the state of the resource before the change, sensitive fields are masked
+func SetBefore(ctx context.Context, value interface{}) {
+	if entry := FromContext(ctx); entry != nil {
+		entry.Before = snapshot(value)
+	}
+}
+
+// SetAfter stores the state of the resource after the change, sensitive fields are masked
+func SetAfter(ctx context.Context, value interface{}) {
+	if entry := FromContext(ctx); entry != nil {
+		entry.After = snapshot(value)
+	}
+}
+
+// snapshot converts value to its redacted JSON form, so that later changes
+// of value do not leak into the entry
+func snapshot(value interface{}) interface{} {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return nil
+	}
+
+	var decoded interface{}
+	if err := json.Unmarshal(raw, &decoded); err != nil {
+		return nil
+	}
+
+	return redact.Value(decoded)
+}
+
+// Diff returns the top level fields that differ between two snapshots. It
+// is empty unless both are JSON objects.
+func Diff(before, after interface{}) map[string]models.AuditChange {
+	from, ok := before.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	to, ok := after.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	diff := make(map[string]models.AuditChange)
+	for key, value := range from {
+		if !reflect.DeepEqual(value, to[key]) {
+			diff[key] = models.AuditChange{Before: value, After: to[key]}
+		}
+	}
+	for key, value := range to {
+		if _, ok := from[key]; !ok {
+			diff[key] = models.AuditChange{Before: nil, After: value}
+		}
+	}
+
+	return diff
+}
@@ -0,0 +1,159 @@
+// Package cursor implements keyset pagination with opaque signed cursors.
+// A cursor holds the (created_at, id) position of the first or last item
+// of a page and the direction to read in from there, so pages stay
+// consistent while rows are inserted.
+//
+// A list adopts it by reading limit+1 rows after the position of the
+// cursor, newest first for Next and oldest first for Prev, and passing
+// them to Page. Backends reached over gRPC need a request carrying the
+// position and the direction to take part.
+package cursor
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrInvalid is returned for cursors that are malformed or were not
+	// signed by the gateway
+	ErrInvalid = errors.New("cursor is invalid")
+	// ErrExpired is returned for cursors older than the TTL of the signer
+	ErrExpired = errors.New("cursor is expired")
+)
+
+// Direction tells whether a cursor reads the items after or before its position
+type Direction string
+
+const (
+	Next Direction = "next"
+	Prev Direction = "prev"
+)
+
+// Position is the sort key of an item, items are listed newest first
+type Position struct {
+	CreatedAt time.Time `json:"t"`
+	ID        string    `json:"id"`
+}
+
+// Cursor is a decoded cursor
+type Cursor struct {
+	Position
+	Direction Direction `json:"d"`
+	// ExpiresAt is set by Encode, in unix seconds
+	ExpiresAt int64 `json:"e"`
+}
+
+// Signer encodes and verifies cursors
+type Signer struct {
+	key []byte
+	ttl time.Duration
+	now func() time.Time
+}
+
+// NewSigner returns a signer whose cursors are valid for ttl
+func NewSigner(key string, ttl time.Duration) *Signer {
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write([]byte("cursor"))
+	return &Signer{key: mac.Sum(nil), ttl: ttl, now: time.Now}
+}
+
+// Encode returns the opaque form of the cursor
+func (s *Signer) Encode(c Cursor) string {
+	c.ExpiresAt = s.now().Add(s.ttl).Unix()
+	payload, _ := json.Marshal(c)
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + s.sign(encoded)
+}
+
+// Decode verifies and decodes a cursor returned by Encode
+func (s *Signer) Decode(value string) (*Cursor, error) {
+	encoded, signature, ok := strings.Cut(value, ".")
+	if !ok || !hmac.Equal([]byte(signature), []byte(s.sign(encoded))) {
+		return nil, ErrInvalid
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, ErrInvalid
+	}
+
+	var c Cursor
+	if err := json.Unmarshal(payload, &c); err != nil {
+		return nil, ErrInvalid
+	}
+	if c.Direction != Next && c.Direction != Prev || c.ID == "" {
+		return nil, ErrInvalid
+	}
+	if s.now().Unix() >= c.ExpiresAt {
+		return nil, ErrExpired
+	}
+
+	return &c, nil
+}
+
+func (s *Signer) sign(encoded string) string {
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write([]byte(encoded))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// Page trims rows read with limit+1 after the position of current, nil for
+// the first page, and returns the page newest first with the cursors of
+// the pages after and before it. A cursor is empty when there is no such page.
+func Page[T any](s *Signer, rows []T, limit int, current *Cursor, position func(T) Position) (items []T, next, prev string) {
+	more := len(rows) > limit
+	if more {
+		rows = rows[:limit]
+	}
+	items = rows
+
+	if current != nil && current.Direction == Prev {
+		// rows before the position are read oldest first
+		for i, j := 0, len(items)-1; i < j; i, j = i+1, j-1 {
+			items[i], items[j] = items[j], items[i]
+		}
+		if len(items) == 0 {
+			return items, "", ""
+		}
+		if more {
+			prev = s.Encode(Cursor{Position: position(items[0]), Direction: Prev})
+		}
+		return items, s.Encode(Cursor{Position: position(items[len(items)-1]), Direction: Next}), prev
+	}
+
+	if len(items) == 0 {
+		return items, "", ""
+	}
+	if more {
+		next = s.Encode(Cursor{Position: position(items[len(items)-1]), Direction: Next})
+	}
+	if current != nil {
+		prev = s.Encode(Cursor{Position: position(items[0]), Direction: Prev})
+	}
+	return items, next, prev
+}
+
+// LinkHeader builds an RFC 8288 Link header value pointing to the pages
+// after and before the current one, u is the URL of the current request
+func LinkHeader(u *url.URL, param, next, prev string) string {
+	var links []string
+	for _, link := range []struct{ rel, cursor string }{{"next", next}, {"prev", prev}} {
+		if link.cursor == "" {
+			continue
+		}
+		query := u.Query()
+		query.Set(param, link.cursor)
+		target := url.URL{Path: u.Path, RawQuery: query.Encode()}
+		links = append(links, fmt.Sprintf("<%s>; rel=\"%s\"", target.String(), link.rel))
+	}
+
+	return strings.Join(links, ", ")
+}
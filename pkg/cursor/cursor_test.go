@@ -0,0 +1,231 @@
+package cursor
+
+import (
+	"encoding/base64"
+	"errors"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+var start = time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+// newTestSigner returns a signer whose clock is moved by the returned func
+func newTestSigner(key string, ttl time.Duration) (*Signer, func(time.Duration)) {
+	now := start
+	signer := NewSigner(key, ttl)
+	signer.now = func() time.Time { return now }
+
+	return signer, func(d time.Duration) { now = now.Add(d) }
+}
+
+func TestRoundTrip(t *testing.T) {
+	signer, _ := newTestSigner("secret", time.Hour)
+	want := Cursor{Position: Position{CreatedAt: start.Add(-time.Minute), ID: "42"}, Direction: Prev}
+
+	got, err := signer.Decode(signer.Encode(want))
+	if err != nil {
+		t.Fatalf("Decode() failed: %v", err)
+	}
+	want.ExpiresAt = start.Add(time.Hour).Unix()
+	if !reflect.DeepEqual(*got, want) {
+		t.Errorf("Decode() = %+v, want %+v", *got, want)
+	}
+}
+
+func TestDecodeRejects(t *testing.T) {
+	signer, _ := newTestSigner("secret", time.Hour)
+	valid := signer.Encode(Cursor{Position: Position{CreatedAt: start, ID: "42"}, Direction: Next})
+	encoded, signature, _ := strings.Cut(valid, ".")
+
+	// resign signs a payload with the key of the gateway, to reach the
+	// checks after the signature
+	resign := func(payload string) string {
+		encoded := base64.RawURLEncoding.EncodeToString([]byte(payload))
+		return encoded + "." + signer.sign(encoded)
+	}
+	tampered := func(payload string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(payload)) + "." + signature
+	}
+	other, _ := newTestSigner("other", time.Hour)
+
+	tests := []struct {
+		name  string
+		value string
+	}{
+		{"empty", ""},
+		{"no signature", encoded},
+		{"wrong signature", encoded + "." + signature[1:] + "A"},
+		{"changed id", tampered(`{"t":"2026-01-02T03:04:05Z","id":"43","d":"next","e":1767326645}`)},
+		{"changed expiry", tampered(`{"t":"2026-01-02T03:04:05Z","id":"42","d":"next","e":4102444800}`)},
+		{"signed with another key", other.Encode(Cursor{Position: Position{CreatedAt: start, ID: "42"}, Direction: Next})},
+		{"not base64", "!!!." + signer.sign("!!!")},
+		{"not json", resign("42")},
+		{"unknown direction", resign(`{"t":"2026-01-02T03:04:05Z","id":"42","d":"up","e":4102444800}`)},
+		{"no id", resign(`{"t":"2026-01-02T03:04:05Z","d":"next","e":4102444800}`)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := signer.Decode(tt.value); !errors.Is(err, ErrInvalid) {
+				t.Errorf("Decode(%q) error = %v, want ErrInvalid", tt.value, err)
+			}
+		})
+	}
+}
+
+func TestExpiry(t *testing.T) {
+	tests := []struct {
+		name    string
+		elapsed time.Duration
+		wantErr error
+	}{
+		{"fresh", 0, nil},
+		{"just before the ttl", time.Hour - time.Second, nil},
+		{"at the ttl", time.Hour, ErrExpired},
+		{"long after", 24 * time.Hour, ErrExpired},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			signer, advance := newTestSigner("secret", time.Hour)
+			value := signer.Encode(Cursor{Position: Position{CreatedAt: start, ID: "42"}, Direction: Next})
+			advance(tt.elapsed)
+
+			if _, err := signer.Decode(value); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Decode() after %s error = %v, want %v", tt.elapsed, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+type row struct {
+	id string
+	at time.Time
+}
+
+func position(r row) Position {
+	return Position{CreatedAt: r.at, ID: r.id}
+}
+
+func TestPage(t *testing.T) {
+	signer, _ := newTestSigner("secret", time.Hour)
+	rows := func(ids ...string) []row {
+		var rows []row
+		for _, id := range ids {
+			rows = append(rows, row{id: id, at: start})
+		}
+		return rows
+	}
+
+	tests := []struct {
+		name     string
+		rows     []row
+		current  *Cursor
+		wantIDs  []string
+		wantNext string
+		wantPrev string
+	}{
+		{
+			name:     "first page with more",
+			rows:     rows("5", "4", "3"),
+			wantIDs:  []string{"5", "4"},
+			wantNext: "4",
+		},
+		{
+			name:    "only page",
+			rows:    rows("5", "4"),
+			wantIDs: []string{"5", "4"},
+		},
+		{
+			name:     "middle page read forward",
+			rows:     rows("3", "2", "1"),
+			current:  &Cursor{Position: Position{ID: "4"}, Direction: Next},
+			wantIDs:  []string{"3", "2"},
+			wantNext: "2",
+			wantPrev: "3",
+		},
+		{
+			name:     "last page read forward",
+			rows:     rows("1"),
+			current:  &Cursor{Position: Position{ID: "2"}, Direction: Next},
+			wantIDs:  []string{"1"},
+			wantPrev: "1",
+		},
+		{
+			name:     "middle page read backward",
+			rows:     rows("4", "5", "6"),
+			current:  &Cursor{Position: Position{ID: "3"}, Direction: Prev},
+			wantIDs:  []string{"5", "4"},
+			wantNext: "4",
+			wantPrev: "5",
+		},
+		{
+			name:     "first page read backward",
+			rows:     rows("4"),
+			current:  &Cursor{Position: Position{ID: "3"}, Direction: Prev},
+			wantIDs:  []string{"4"},
+			wantNext: "4",
+		},
+		{
+			name:    "nothing after the cursor",
+			rows:    nil,
+			current: &Cursor{Position: Position{ID: "1"}, Direction: Next},
+		},
+	}
+
+	idOf := func(value string) string {
+		if value == "" {
+			return ""
+		}
+		c, err := signer.Decode(value)
+		if err != nil {
+			t.Fatalf("Page() returned an undecodable cursor: %v", err)
+		}
+		return c.ID
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items, next, prev := Page(signer, tt.rows, 2, tt.current, position)
+
+			var ids []string
+			for _, item := range items {
+				ids = append(ids, item.id)
+			}
+			if !reflect.DeepEqual(ids, tt.wantIDs) {
+				t.Errorf("Page() items = %v, want %v", ids, tt.wantIDs)
+			}
+			if got := idOf(next); got != tt.wantNext {
+				t.Errorf("Page() next points at %q, want %q", got, tt.wantNext)
+			}
+			if got := idOf(prev); got != tt.wantPrev {
+				t.Errorf("Page() prev points at %q, want %q", got, tt.wantPrev)
+			}
+		})
+	}
+}
+
+func TestLinkHeader(t *testing.T) {
+	u, _ := url.Parse("/v2/auth/admins?limit=2&cursor=old")
+
+	tests := []struct {
+		name       string
+		next, prev string
+		want       string
+	}{
+		{"both", "n", "p", `</v2/auth/admins?cursor=n&limit=2>; rel="next", </v2/auth/admins?cursor=p&limit=2>; rel="prev"`},
+		{"next only", "n", "", `</v2/auth/admins?cursor=n&limit=2>; rel="next"`},
+		{"none", "", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := LinkHeader(u, "cursor", tt.next, tt.prev); got != tt.want {
+				t.Errorf("LinkHeader() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
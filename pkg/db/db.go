@@ -1,26 +1,40 @@
 package db
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"myproject/admin-api-gateway/config"
-	_ "github.com/lib/pq" 
+	"time"
+
+	_ "github.com/lib/pq"
 )
 
-func ConnectToDB(cfg config.Config) (*sql.DB, func(), error) {
-	psqlString := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=disable",
+func PostgresDSN(cfg config.Config) string {
+	return fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=disable",
 		cfg.PostgresHost,
 		cfg.PostgresPort,
 		cfg.PostgresUser,
 		cfg.PostgresPassword,
 		cfg.PostgresDatabase,
 	)
+}
 
-	connDB, err := sql.Open("postgres", psqlString)
+func ConnectToDB(cfg config.Config) (*sql.DB, func(), error) {
+	connDB, err := sql.Open("postgres", PostgresDSN(cfg))
 	if err != nil {
 		return nil, nil, err
 	}
 
+	// sql.Open does not connect, ping so a wrong config fails on startup
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*time.Duration(cfg.CtxTimeout))
+	defer cancel()
+
+	if err := connDB.PingContext(ctx); err != nil {
+		connDB.Close()
+		return nil, nil, fmt.Errorf("cannot ping postgres at %s:%d: %w", cfg.PostgresHost, cfg.PostgresPort, err)
+	}
+
 	cleanUpFunc := func() {
 		connDB.Close()
 	}
@@ -0,0 +1,52 @@
+package etag
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+)
+
+// Of returns a strong ETag derived from the JSON encoding of the resource
+func Of(resource interface{}) string {
+	data, err := json.Marshal(resource)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// Match reports whether an If-None-Match header value matches the tag with
+// the weak comparison. The header may hold "*" or a comma separated list of
+// tags, weak tags are compared by their opaque value.
+func Match(header, tag string) bool {
+	return match(header, tag, func(candidate, tag string) bool {
+		return strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(tag, "W/")
+	})
+}
+
+// MatchStrong reports whether an If-Match header value matches the tag with
+// the strong comparison: weak tags never match, as RFC 9110 requires for
+// preconditions on updates.
+func MatchStrong(header, tag string) bool {
+	return match(header, tag, func(candidate, tag string) bool {
+		return !strings.HasPrefix(candidate, "W/") && !strings.HasPrefix(tag, "W/") && candidate == tag
+	})
+}
+
+func match(header, tag string, equal func(candidate, tag string) bool) bool {
+	if tag == "" {
+		return false
+	}
+
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || equal(candidate, tag) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,62 @@
+package etag
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestOf(t *testing.T) {
+	type doctor struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	}
+
+	tag := Of(doctor{ID: "1", Name: "Ann"})
+	if !strings.HasPrefix(tag, `"`) || !strings.HasSuffix(tag, `"`) || len(tag) != 34 {
+		t.Fatalf("Of() = %s, want a quoted strong tag of 32 hex digits", tag)
+	}
+	if again := Of(doctor{ID: "1", Name: "Ann"}); again != tag {
+		t.Errorf("Of() of an equal resource = %s, want %s", again, tag)
+	}
+	if changed := Of(doctor{ID: "1", Name: "Bob"}); changed == tag {
+		t.Error("Of() did not change with the resource")
+	}
+	if unencodable := Of(func() {}); unencodable != "" {
+		t.Errorf("Of() of a func = %s, want no tag", unencodable)
+	}
+}
+
+func TestMatch(t *testing.T) {
+	const tag = `"abc"`
+
+	tests := []struct {
+		name       string
+		header     string
+		tag        string
+		wantWeak   bool
+		wantStrong bool
+	}{
+		{"same strong tag", `"abc"`, tag, true, true},
+		{"other tag", `"abd"`, tag, false, false},
+		{"weak header tag", `W/"abc"`, tag, true, false},
+		{"weak resource tag", `"abc"`, `W/"abc"`, true, false},
+		{"both weak", `W/"abc"`, `W/"abc"`, true, false},
+		{"any", `*`, tag, true, true},
+		{"in a list", `"x", "abc" ,"y"`, tag, true, true},
+		{"weak in a list", `"x", W/"abc"`, tag, true, false},
+		{"unquoted", `abc`, tag, false, false},
+		{"empty header", ``, tag, false, false},
+		{"resource without a tag", `*`, ``, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Match(tt.header, tt.tag); got != tt.wantWeak {
+				t.Errorf("Match(%s, %s) = %v, want %v", tt.header, tt.tag, got, tt.wantWeak)
+			}
+			if got := MatchStrong(tt.header, tt.tag); got != tt.wantStrong {
+				t.Errorf("MatchStrong(%s, %s) = %v, want %v", tt.header, tt.tag, got, tt.wantStrong)
+			}
+		})
+	}
+}
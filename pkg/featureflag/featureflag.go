@@ -0,0 +1,113 @@
+package featureflag
+
+import (
+	"context"
+	"myproject/admin-api-gateway/api/models"
+	"myproject/admin-api-gateway/pkg/logger"
+	"myproject/admin-api-gateway/storage/repo"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// Flags known to the gateway. Other names can be stored as well and
+// checked by handlers with Enabled.
+const (
+	// Maintenance rejects every request to its routes, or to the whole API without routes
+	Maintenance = "maintenance"
+	// ReadOnly rejects mutating requests to its routes, or to the whole API without routes
+	ReadOnly = "read_only"
+	// RegistrationClosed disables the self-registration of users
+	RegistrationClosed = "registration_closed"
+	// DoctorRegistrationClosed disables the self-registration of doctors
+	DoctorRegistrationClosed = "doctor_registration_closed"
+)
+
+// Flags serves the flags from a snapshot refreshed in the background, so
+// that checking a flag never waits for the store. When the store is down
+// the last snapshot keeps being served.
+type Flags struct {
+	store    repo.FeatureFlagsI
+	log      logger.Logger
+	snapshot atomic.Pointer[map[string]*models.FeatureFlag]
+}
+
+func New(store repo.FeatureFlagsI, log logger.Logger) *Flags {
+	f := &Flags{
+		store: store,
+		log:   log,
+	}
+	f.snapshot.Store(&map[string]*models.FeatureFlag{})
+
+	return f
+}
+
+// Refresh replaces the snapshot with the flags in the store
+func (f *Flags) Refresh(ctx context.Context) error {
+	flags, err := f.store.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	snapshot := make(map[string]*models.FeatureFlag, len(flags))
+	for _, flag := range flags {
+		snapshot[flag.Name] = flag
+	}
+	f.snapshot.Store(&snapshot)
+
+	return nil
+}
+
+// Run refreshes the snapshot every interval until ctx is cancelled
+func (f *Flags) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := f.Refresh(ctx); err != nil && ctx.Err() == nil {
+			f.log.Warn("cannot refresh feature flags, serving the last known ones", logger.Error(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Get returns the flag if it is enabled
+func (f *Flags) Get(name string) (*models.FeatureFlag, bool) {
+	flag, ok := (*f.snapshot.Load())[name]
+	if !ok || !flag.Enabled {
+		return nil, false
+	}
+
+	return flag, true
+}
+
+// Enabled reports whether the flag is enabled
+func (f *Flags) Enabled(name string) bool {
+	_, ok := f.Get(name)
+	return ok
+}
+
+// Applies reports whether the flag is enabled for the route. A flag without
+// routes applies to every route, otherwise to the routes under its prefixes.
+func (f *Flags) Applies(name, route string) (*models.FeatureFlag, bool) {
+	flag, ok := f.Get(name)
+	if !ok {
+		return nil, false
+	}
+	if len(flag.Routes) == 0 {
+		return flag, true
+	}
+
+	for _, prefix := range flag.Routes {
+		if strings.HasPrefix(route, prefix) {
+			return flag, true
+		}
+	}
+
+	return nil, false
+}
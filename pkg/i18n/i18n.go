@@ -0,0 +1,128 @@
+// Package i18n translates the messages of the API. Messages are keyed by
+// their English text, so English needs no catalog and a message missing
+// from a catalog falls back to English.
+package i18n
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+// Languages the API speaks
+const (
+	English = "en"
+	Uzbek   = "uz"
+	Russian = "ru"
+)
+
+// Default is the language used when nothing better matches
+const Default = English
+
+// Header is the response header naming the language of the response
+const Header = "Content-Language"
+
+//go:embed locales/*.json
+var locales embed.FS
+
+var (
+	supported = []string{English, Uzbek, Russian}
+	matcher   = language.NewMatcher([]language.Tag{language.English, language.Uzbek, language.Russian})
+	catalogs  = mustLoadCatalogs()
+)
+
+type ctxKey struct{}
+
+func mustLoadCatalogs() map[string]map[string]string {
+	files, err := locales.ReadDir("locales")
+	if err != nil {
+		panic(err)
+	}
+
+	catalogs := make(map[string]map[string]string, len(files))
+	for _, file := range files {
+		data, err := locales.ReadFile(path.Join("locales", file.Name()))
+		if err != nil {
+			panic(err)
+		}
+
+		var catalog map[string]string
+		if err := json.Unmarshal(data, &catalog); err != nil {
+			panic(fmt.Sprintf("i18n: parse %s: %v", file.Name(), err))
+		}
+		catalogs[strings.TrimSuffix(file.Name(), ".json")] = catalog
+	}
+
+	return catalogs
+}
+
+// Supported lists the languages the API speaks
+func Supported() []string {
+	return append([]string(nil), supported...)
+}
+
+// IsSupported reports whether lang is one of the supported languages
+func IsSupported(lang string) bool {
+	for _, l := range supported {
+		if l == lang {
+			return true
+		}
+	}
+	return false
+}
+
+// Negotiate picks the supported language matching an Accept-Language
+// header best, English when none does
+func Negotiate(acceptLanguage string) string {
+	if acceptLanguage == "" {
+		return Default
+	}
+
+	tag, _ := language.MatchStrings(matcher, acceptLanguage)
+	base, _ := tag.Base()
+	if !IsSupported(base.String()) {
+		return Default
+	}
+
+	return base.String()
+}
+
+// T translates message to lang, messages missing from the catalog of lang
+// are returned untranslated
+func T(lang, message string) string {
+	if translated, ok := catalogs[lang][message]; ok && translated != "" {
+		return translated
+	}
+	return message
+}
+
+// Tf translates the format and fills it in with args
+func Tf(lang, format string, args ...interface{}) string {
+	return fmt.Sprintf(T(lang, format), args...)
+}
+
+// PreferenceKey is the key the language saved by a caller is stored under
+func PreferenceKey(subject string) string {
+	return "language:" + subject
+}
+
+// NewContext returns a copy of ctx carrying the language of the request
+func NewContext(ctx context.Context, lang string) context.Context {
+	return context.WithValue(ctx, ctxKey{}, lang)
+}
+
+// FromContext returns the language of the request, or the default one
+func FromContext(ctx context.Context) string {
+	if ctx == nil {
+		return Default
+	}
+	if lang, ok := ctx.Value(ctxKey{}).(string); ok && lang != "" {
+		return lang
+	}
+	return Default
+}
@@ -0,0 +1,125 @@
+package listquery
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Fields reads the values items are filtered and sorted on, keyed by the
+// field names of the Spec. Values are strings, integers, floats or bools.
+type Fields[T any] map[string]func(item T) interface{}
+
+// Apply filters, sorts and pages items in the gateway, for backends that
+// cannot do it themselves. It returns the page and the number of items
+// matching the filters. Filters without a field reader are taken as
+// applied by the backend already.
+func Apply[T any](items []T, q Query, fields Fields[T]) ([]T, int) {
+	matching := make([]T, 0, len(items))
+	for _, item := range items {
+		if matches(item, q.Filters, fields) {
+			matching = append(matching, item)
+		}
+	}
+
+	if len(q.Sort) > 0 {
+		sort.SliceStable(matching, func(i, j int) bool {
+			for _, key := range q.Sort {
+				read, ok := fields[key.Field]
+				if !ok {
+					continue
+				}
+				c := compare(read(matching[i]), read(matching[j]))
+				if c == 0 {
+					continue
+				}
+				if key.Desc {
+					return c > 0
+				}
+				return c < 0
+			}
+			return false
+		})
+	}
+
+	total := len(matching)
+	start := q.Offset()
+	if start < 0 || start >= total {
+		return []T{}, total
+	}
+	end := start + q.Limit
+	if end > total {
+		end = total
+	}
+
+	return matching[start:end], total
+}
+
+func matches[T any](item T, filters map[string]string, fields Fields[T]) bool {
+	for name, value := range filters {
+		read, ok := fields[name]
+		if !ok {
+			continue
+		}
+		if !strings.EqualFold(fmt.Sprint(read(item)), value) {
+			return false
+		}
+	}
+	return true
+}
+
+func compare(a, b interface{}) int {
+	switch a := a.(type) {
+	case string:
+		return strings.Compare(strings.ToLower(a), strings.ToLower(b.(string)))
+	case bool:
+		switch {
+		case a == b.(bool):
+			return 0
+		case a:
+			return 1
+		default:
+			return -1
+		}
+	}
+
+	x, y := toFloat(a), toFloat(b)
+	switch {
+	case x < y:
+		return -1
+	case x > y:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func toFloat(v interface{}) float64 {
+	switch v := v.(type) {
+	case int:
+		return float64(v)
+	case int32:
+		return float64(v)
+	case int64:
+		return float64(v)
+	case float32:
+		return float64(v)
+	case float64:
+		return v
+	}
+	return 0
+}
+
+// NeedsGateway reports whether the gateway has to filter or sort the list
+// itself, i.e. the query sorts or filters on a field with a reader
+func (f Fields[T]) NeedsGateway(q Query) bool {
+	if len(q.Sort) > 0 {
+		return true
+	}
+	for name := range q.Filters {
+		if _, ok := f[name]; ok {
+			return true
+		}
+	}
+	return false
+}
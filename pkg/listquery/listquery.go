@@ -0,0 +1,224 @@
+// Package listquery parses the pagination, filter and sort query parameters
+// of list endpoints, e.g. ?department_id=3&gender=male&sort=-created_at&page=2&limit=20.
+// Every endpoint whitelists the fields it can be filtered and sorted on,
+// other parameters are rejected.
+package listquery
+
+import (
+	"errors"
+	"math"
+	"myproject/admin-api-gateway/api/models"
+	"net/url"
+	"strconv"
+	"strings"
+
+	validation "github.com/go-ozzo/ozzo-validation/v3"
+)
+
+// Reserved query parameters, the others name filters
+const (
+	PageParam   = "page"
+	LimitParam  = "limit"
+	SortParam   = "sort"
+	CursorParam = "cursor"
+)
+
+const (
+	defaultLimit = 10
+	maxLimit     = 100
+)
+
+// Kind is the type of the value a field is filtered on
+type Kind int
+
+const (
+	String Kind = iota
+	Int
+	Bool
+)
+
+// Filter describes a field a list can be filtered on
+type Filter struct {
+	Kind Kind
+	// Values whitelists the accepted values, any value is accepted when empty
+	Values []string
+}
+
+// Spec whitelists the fields of a list endpoint
+type Spec struct {
+	Filters map[string]Filter
+	// Sorts lists the fields the list can be sorted on
+	Sorts []string
+	// DefaultSort is used without a sort parameter, e.g. "-created_at".
+	// When empty the order of the backend is kept.
+	DefaultSort string
+	// MaxLimit caps the page size, 100 when zero
+	MaxLimit int
+	// Cursor allows keyset pagination: with a cursor parameter, empty for
+	// the first page, the list is read newest first from the cursor and
+	// page and sort are rejected
+	Cursor bool
+}
+
+// SortField is one key of a sort, "-name" sorts by name descending
+type SortField struct {
+	Field string
+	Desc  bool
+}
+
+// Query is a parsed list request
+type Query struct {
+	Page    int
+	Limit   int
+	Filters map[string]string
+	Sort    []SortField
+	// Paginated by cursor, Cursor is empty for the first page
+	CursorMode bool
+	Cursor     string
+}
+
+// Offset is the number of items before the page
+func (q Query) Offset() int {
+	return (q.Page - 1) * q.Limit
+}
+
+// Filter returns the value the list is filtered on by field
+func (q Query) Filter(field string) (string, bool) {
+	value, ok := q.Filters[field]
+	return value, ok
+}
+
+// Parse reads the query of a list request. Its errors are
+// validation.Errors keyed by the rejected parameter.
+func Parse(values url.Values, spec Spec) (Query, error) {
+	errs := validation.Errors{}
+	query := Query{
+		Page:    1,
+		Limit:   defaultLimit,
+		Filters: make(map[string]string),
+	}
+
+	limitCap := spec.MaxLimit
+	if limitCap == 0 {
+		limitCap = maxLimit
+	}
+	if raw := values.Get(PageParam); raw != "" {
+		page, err := strconv.Atoi(raw)
+		switch {
+		case err != nil || page < 1:
+			errs[PageParam] = ruleError(models.RuleFormat, "should be a positive number")
+		case page > math.MaxInt/limitCap:
+			// keeps the offset of the page from overflowing
+			errs[PageParam] = ruleError(models.RuleLength, "is larger than the maximum page number")
+		}
+		query.Page = page
+	}
+	if raw := values.Get(LimitParam); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		switch {
+		case err != nil || limit < 1:
+			errs[LimitParam] = ruleError(models.RuleFormat, "should be a positive number")
+		case limit > limitCap:
+			errs[LimitParam] = ruleError(models.RuleLength, "is larger than the maximum page size")
+		}
+		query.Limit = limit
+	}
+
+	if spec.Cursor && values.Has(CursorParam) {
+		query.CursorMode = true
+		query.Cursor = values.Get(CursorParam)
+		for _, param := range []string{PageParam, SortParam} {
+			if values.Has(param) {
+				errs[param] = ruleError(models.RuleUnsupported, "cannot be combined with a cursor")
+			}
+		}
+	}
+
+	sort := values.Get(SortParam)
+	if sort == "" && !query.CursorMode {
+		sort = spec.DefaultSort
+	}
+	if sort != "" {
+		fields, err := parseSort(sort, spec.Sorts)
+		if err != nil {
+			errs[SortParam] = err
+		}
+		query.Sort = fields
+	}
+
+	for name := range values {
+		if name == PageParam || name == LimitParam || name == SortParam || name == CursorParam && spec.Cursor {
+			continue
+		}
+
+		filter, ok := spec.Filters[name]
+		if !ok {
+			errs[name] = ruleError(models.RuleUnsupported, "is not a supported parameter")
+			continue
+		}
+		value, err := filter.normalize(values.Get(name))
+		if err != nil {
+			errs[name] = err
+			continue
+		}
+		query.Filters[name] = value
+	}
+
+	if len(errs) > 0 {
+		return Query{}, errs
+	}
+	return query, nil
+}
+
+func parseSort(sort string, allowed []string) ([]SortField, error) {
+	var fields []SortField
+	for _, key := range strings.Split(sort, ",") {
+		field := SortField{Field: strings.TrimSpace(key)}
+		if strings.HasPrefix(field.Field, "-") {
+			field.Field, field.Desc = field.Field[1:], true
+		}
+		if !contains(allowed, field.Field) {
+			return nil, ruleError(models.RuleOneOf, "cannot be sorted on this field")
+		}
+		fields = append(fields, field)
+	}
+
+	return fields, nil
+}
+
+// normalize checks value and returns it in the canonical form of its kind,
+// e.g. "1" and "T" become "true", so it compares equal to the item values
+func (f Filter) normalize(value string) (string, error) {
+	switch f.Kind {
+	case Int:
+		parsed, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return "", ruleError(models.RuleType, "must be a number")
+		}
+		value = strconv.FormatInt(parsed, 10)
+	case Bool:
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			return "", ruleError(models.RuleType, "must be a boolean")
+		}
+		value = strconv.FormatBool(parsed)
+	}
+	if len(f.Values) > 0 && !contains(f.Values, value) {
+		return "", ruleError(models.RuleOneOf, "is not one of the supported values")
+	}
+
+	return value, nil
+}
+
+func ruleError(rule, message string) error {
+	return models.RuleError{Rule: rule, Err: errors.New(message)}
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,229 @@
+package listquery
+
+import (
+	"errors"
+	"math"
+	"myproject/admin-api-gateway/api/models"
+	"net/url"
+	"reflect"
+	"strconv"
+	"testing"
+
+	validation "github.com/go-ozzo/ozzo-validation/v3"
+)
+
+var doctorSpec = Spec{
+	Filters: map[string]Filter{
+		"department_id": {Kind: Int},
+		"gender":        {Values: []string{"male", "female"}},
+		"is_verified":   {Kind: Bool},
+	},
+	Sorts:       []string{"full_name", "created_at"},
+	DefaultSort: "-created_at",
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		spec  Spec
+		want  Query
+	}{
+		{
+			name:  "defaults",
+			query: "",
+			spec:  doctorSpec,
+			want:  Query{Page: 1, Limit: 10, Filters: map[string]string{}, Sort: []SortField{{Field: "created_at", Desc: true}}},
+		},
+		{
+			name:  "page, limit and sort",
+			query: "page=3&limit=20&sort=full_name,-created_at",
+			spec:  doctorSpec,
+			want: Query{Page: 3, Limit: 20, Filters: map[string]string{},
+				Sort: []SortField{{Field: "full_name"}, {Field: "created_at", Desc: true}}},
+		},
+		{
+			name:  "filters are normalized",
+			query: "department_id=007&gender=female&is_verified=1",
+			spec:  doctorSpec,
+			want: Query{Page: 1, Limit: 10,
+				Filters: map[string]string{"department_id": "7", "gender": "female", "is_verified": "true"},
+				Sort:    []SortField{{Field: "created_at", Desc: true}}},
+		},
+		{
+			name:  "no default sort",
+			query: "limit=5",
+			spec:  Spec{},
+			want:  Query{Page: 1, Limit: 5, Filters: map[string]string{}},
+		},
+		{
+			name:  "first page of a cursor",
+			query: "cursor=",
+			spec:  Spec{Cursor: true, DefaultSort: "-created_at"},
+			want:  Query{Page: 1, Limit: 10, Filters: map[string]string{}, CursorMode: true},
+		},
+		{
+			name:  "next page of a cursor",
+			query: "cursor=abc&limit=50",
+			spec:  Spec{Cursor: true},
+			want:  Query{Page: 1, Limit: 50, Filters: map[string]string{}, CursorMode: true, Cursor: "abc"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values, err := url.ParseQuery(tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := Parse(values, tt.spec)
+			if err != nil {
+				t.Fatalf("Parse(%q) failed: %v", tt.query, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse(%q) = %+v, want %+v", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRejects(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		spec  Spec
+		field string
+		rule  string
+	}{
+		{"unknown filter", "salary=100", doctorSpec, "salary", models.RuleUnsupported},
+		{"unknown sort field", "sort=salary", doctorSpec, SortParam, models.RuleOneOf},
+		{"unknown descending sort field", "sort=full_name,-salary", doctorSpec, SortParam, models.RuleOneOf},
+		{"int filter", "department_id=three", doctorSpec, "department_id", models.RuleType},
+		{"bool filter", "is_verified=yes", doctorSpec, "is_verified", models.RuleType},
+		{"value out of the whitelist", "gender=other", doctorSpec, "gender", models.RuleOneOf},
+		{"page is not a number", "page=first", doctorSpec, PageParam, models.RuleFormat},
+		{"page is zero", "page=0", doctorSpec, PageParam, models.RuleFormat},
+		{"page overflows the offset", "page=" + strconv.Itoa(math.MaxInt/maxLimit+1), doctorSpec, PageParam, models.RuleLength},
+		{"limit is negative", "limit=-1", doctorSpec, LimitParam, models.RuleFormat},
+		{"limit over the cap", "limit=101", doctorSpec, LimitParam, models.RuleLength},
+		{"limit over the spec cap", "limit=11", Spec{MaxLimit: 10}, LimitParam, models.RuleLength},
+		{"cursor without support", "cursor=abc", doctorSpec, CursorParam, models.RuleUnsupported},
+		{"cursor with a page", "cursor=abc&page=2", Spec{Cursor: true}, PageParam, models.RuleUnsupported},
+		{"cursor with a sort", "cursor=&sort=-created_at", Spec{Cursor: true, Sorts: []string{"created_at"}}, SortParam, models.RuleUnsupported},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values, err := url.ParseQuery(tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, err = Parse(values, tt.spec)
+
+			var errs validation.Errors
+			if !errors.As(err, &errs) {
+				t.Fatalf("Parse(%q) error = %v, want validation.Errors", tt.query, err)
+			}
+			var ruleErr models.RuleError
+			if !errors.As(errs[tt.field], &ruleErr) {
+				t.Fatalf("Parse(%q) errors = %v, want a rule error on %s", tt.query, errs, tt.field)
+			}
+			if ruleErr.Rule != tt.rule {
+				t.Errorf("Parse(%q) rule on %s = %s, want %s", tt.query, tt.field, ruleErr.Rule, tt.rule)
+			}
+		})
+	}
+}
+
+type doctor struct {
+	name       string
+	department int64
+	verified   bool
+}
+
+var doctorFields = Fields[doctor]{
+	"full_name":     func(d doctor) interface{} { return d.name },
+	"department_id": func(d doctor) interface{} { return d.department },
+	"is_verified":   func(d doctor) interface{} { return d.verified },
+}
+
+func TestApply(t *testing.T) {
+	doctors := []doctor{
+		{"Carol", 1, true},
+		{"alice", 2, false},
+		{"Bob", 1, false},
+		{"dave", 1, true},
+	}
+	names := func(items []doctor) []string {
+		var names []string
+		for _, item := range items {
+			names = append(names, item.name)
+		}
+		return names
+	}
+
+	tests := []struct {
+		name      string
+		query     Query
+		want      []string
+		wantTotal int
+	}{
+		{
+			name:      "sorted case-insensitively",
+			query:     Query{Page: 1, Limit: 10, Sort: []SortField{{Field: "full_name"}}},
+			want:      []string{"alice", "Bob", "Carol", "dave"},
+			wantTotal: 4,
+		},
+		{
+			name:      "filtered and sorted descending",
+			query:     Query{Page: 1, Limit: 10, Filters: map[string]string{"department_id": "1"}, Sort: []SortField{{Field: "full_name", Desc: true}}},
+			want:      []string{"dave", "Carol", "Bob"},
+			wantTotal: 3,
+		},
+		{
+			name:      "bool filter",
+			query:     Query{Page: 1, Limit: 10, Filters: map[string]string{"is_verified": "true"}},
+			want:      []string{"Carol", "dave"},
+			wantTotal: 2,
+		},
+		{
+			name:      "second page",
+			query:     Query{Page: 2, Limit: 3, Sort: []SortField{{Field: "full_name"}}},
+			want:      []string{"dave"},
+			wantTotal: 4,
+		},
+		{
+			name:      "ties keep the backend order",
+			query:     Query{Page: 1, Limit: 10, Sort: []SortField{{Field: "department_id"}}},
+			want:      []string{"Carol", "Bob", "dave", "alice"},
+			wantTotal: 4,
+		},
+		{
+			name:      "filter left to the backend",
+			query:     Query{Page: 1, Limit: 10, Filters: map[string]string{"gender": "male"}},
+			want:      []string{"Carol", "alice", "Bob", "dave"},
+			wantTotal: 4,
+		},
+		{
+			name:      "page past the end",
+			query:     Query{Page: 3, Limit: 3},
+			want:      nil,
+			wantTotal: 4,
+		},
+		{
+			name:      "overflowing offset",
+			query:     Query{Page: math.MaxInt/2 + 2, Limit: 2},
+			want:      nil,
+			wantTotal: 4,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, total := Apply(doctors, tt.query, doctorFields)
+			if !reflect.DeepEqual(names(got), tt.want) || total != tt.wantTotal {
+				t.Errorf("Apply() = %v, %d, want %v, %d", names(got), total, tt.want, tt.wantTotal)
+			}
+		})
+	}
+}
@@ -1,8 +1,14 @@
 package logger
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"myproject/admin-api-gateway/pkg/requestid"
+	"strings"
 	"time"
 
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
@@ -13,6 +19,10 @@ type Field = zapcore.Field
 var (
 	// Int ..
 	Int = zap.Int
+	// Int64 ...
+	Int64 = zap.Int64
+	// Duration ...
+	Duration = zap.Duration
 	// String ...
 	String = zap.String
 	// Error ...
@@ -34,7 +44,8 @@ type Logger interface {
 }
 
 type loggerImpl struct {
-	zap *zap.Logger
+	zap   *zap.Logger
+	level zap.AtomicLevel
 }
 
 var (
@@ -47,8 +58,10 @@ func New(level string, namespace string) Logger {
 		level = LevelInfo
 	}
 
+	atomicLevel := zap.NewAtomicLevelAt(parseLevel(level))
 	logger := loggerImpl{
-		zap: newZapLogger(level, time.RFC3339),
+		zap:   newZapLogger(atomicLevel, time.RFC3339),
+		level: atomicLevel,
 	}
 
 	logger.zap = logger.zap.Named(namespace)
@@ -95,7 +108,8 @@ func WithFields(l Logger, fields ...Field) Logger {
 	switch v := l.(type) {
 	case *loggerImpl:
 		return &loggerImpl{
-			zap: v.zap.With(fields...),
+			zap:   v.zap.With(fields...),
+			level: v.level,
 		}
 	default:
 		l.Info("logger.WithFields: invalid logger type")
@@ -103,6 +117,59 @@ func WithFields(l Logger, fields ...Field) Logger {
 	}
 }
 
+// WithContext returns a logger which adds the request id and the trace id stored in ctx to every line
+func WithContext(l Logger, ctx context.Context) Logger {
+	var fields []Field
+	if id := requestid.FromContext(ctx); id != "" {
+		fields = append(fields, String("request_id", id))
+	}
+	if span := trace.SpanContextFromContext(ctx); span.HasTraceID() {
+		fields = append(fields, String("trace_id", span.TraceID().String()))
+	}
+
+	if len(fields) == 0 {
+		return l
+	}
+
+	return WithFields(l, fields...)
+}
+
+// SetLevel changes the level of l and of every logger derived from it
+func SetLevel(l Logger, level string) error {
+	level = strings.ToLower(level)
+	if !ValidLevel(level) {
+		return fmt.Errorf("unknown log level %q", level)
+	}
+
+	switch v := l.(type) {
+	case *loggerImpl:
+		v.level.SetLevel(parseLevel(level))
+		return nil
+	default:
+		return errors.New("logger.SetLevel: invalid logger type")
+	}
+}
+
+// GetLevel returns the level l currently logs at
+func GetLevel(l Logger) string {
+	switch v := l.(type) {
+	case *loggerImpl:
+		return v.level.Level().String()
+	default:
+		return LevelInfo
+	}
+}
+
+// ValidLevel reports whether level can be set at runtime
+func ValidLevel(level string) bool {
+	switch level {
+	case LevelDebug, LevelInfo, LevelWarn, LevelError:
+		return true
+	}
+
+	return false
+}
+
 // Cleanup ...
 func Cleanup(l Logger) error {
 	switch v := l.(type) {
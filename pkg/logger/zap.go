@@ -8,16 +8,14 @@ import (
 	"go.uber.org/zap/zapcore"
 )
 
-func newZapLogger(level, timeFormat string) *zap.Logger {
-
-	globalLevel := parseLevel(level)
+func newZapLogger(level zap.AtomicLevel, timeFormat string) *zap.Logger {
 
 	highPriority := zap.LevelEnablerFunc(func(lvl zapcore.Level) bool {
 		return lvl >= zapcore.ErrorLevel
 	})
 
 	lowPriority := zap.LevelEnablerFunc(func(lvl zapcore.Level) bool {
-		return lvl >= globalLevel && lvl < zapcore.ErrorLevel
+		return level.Enabled(lvl) && lvl < zapcore.ErrorLevel
 	})
 
 	consoleInfos := zapcore.Lock(os.Stdout)
@@ -65,7 +63,7 @@ func parseLevel(level string) zapcore.Level {
 // GetZapLogger extracts zap struct from given logger interface
 func GetZapLogger(l Logger) *zap.Logger {
 	if l == nil {
-		return newZapLogger(LevelInfo, time.RFC3339)
+		return newZapLogger(zap.NewAtomicLevel(), time.RFC3339)
 	}
 
 	switch v := l.(type) {
@@ -73,6 +71,6 @@ func GetZapLogger(l Logger) *zap.Logger {
 		return v.zap
 	default:
 		l.Info("logger.WithFields: invalid logger type, creating a new zap logger", String("level", LevelInfo), String("time_format", time.RFC3339))
-		return newZapLogger(LevelInfo, time.RFC3339)
+		return newZapLogger(zap.NewAtomicLevel(), time.RFC3339)
 	}
 }
@@ -0,0 +1,105 @@
+package metrics
+
+import (
+	"database/sql"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const namespace = "admin_api_gateway"
+
+var (
+	// HTTPRequests counts served requests by route template and status
+	HTTPRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "Number of HTTP requests by method, route template and status.",
+	}, []string{"method", "route", "status"})
+
+	// HTTPDuration observes request latency by route template and status
+	HTTPDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP request latency by method, route template and status.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	// GRPCClientRequests counts calls to the backend services by method and status code
+	GRPCClientRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "grpc_client_requests_total",
+		Help:      "Number of gRPC calls to the backend services by service, method and code.",
+	}, []string{"service", "method", "code"})
+
+	// GRPCClientDuration observes the latency of calls to the backend services
+	GRPCClientDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "grpc_client_request_duration_seconds",
+		Help:      "Latency of gRPC calls to the backend services by service and method.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"service", "method"})
+
+	// GRPCCircuitBreakerState reports the breaker state per backend: 0 closed, 1 half-open, 2 open
+	GRPCCircuitBreakerState = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "grpc_circuit_breaker_state",
+		Help:      "State of the circuit breaker per backend service: 0 closed, 1 half-open, 2 open.",
+	}, []string{"service"})
+
+	// CasbinEnforceDuration observes how long authorization decisions take
+	CasbinEnforceDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "casbin_enforce_duration_seconds",
+		Help:      "Latency of casbin enforce calls by result.",
+		Buckets:   []float64{.0001, .00025, .0005, .001, .0025, .005, .01, .025, .05, .1},
+	}, []string{"result"})
+
+	// AuditEntries counts audit log entries by result: written, dropped or failed
+	AuditEntries = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "audit_entries_total",
+		Help:      "Number of audit log entries by result: written, dropped when the buffer is full, or failed to write.",
+	}, []string{"result"})
+
+	// EmailsSent counts verification emails by result
+	EmailsSent = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "emails_sent_total",
+		Help:      "Number of verification emails by result.",
+	}, []string{"result"})
+)
+
+// RegisterRedisPool exposes the active and idle connections of the redigo pool
+func RegisterRedisPool(pool *redis.Pool) {
+	promauto.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "redis_pool_active_connections",
+		Help:      "Number of connections in the redis pool, idle ones included.",
+	}, func() float64 {
+		return float64(pool.Stats().ActiveCount)
+	})
+
+	promauto.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "redis_pool_idle_connections",
+		Help:      "Number of idle connections in the redis pool.",
+	}, func() float64 {
+		return float64(pool.Stats().IdleCount)
+	})
+
+	promauto.NewCounterFunc(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "redis_pool_wait_total",
+		Help:      "Number of times a caller waited for a redis pool connection.",
+	}, func() float64 {
+		return float64(pool.Stats().WaitCount)
+	})
+}
+
+// RegisterDB exposes the database/sql pool stats of db
+func RegisterDB(db *sql.DB, name string) {
+	prometheus.MustRegister(collectors.NewDBStatsCollector(db, name))
+}
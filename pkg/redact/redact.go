@@ -0,0 +1,91 @@
+package redact
+
+import (
+	"encoding/json"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// Mask replaces every redacted value
+const Mask = "[REDACTED]"
+
+var (
+	sensitiveKeys = []string{"password", "code", "token", "secret", "email", "authorization", "api_key"}
+	emailPattern  = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+)
+
+// IsSensitive reports whether values stored under key must not be logged,
+// header style keys such as X-API-Key count as api_key
+func IsSensitive(key string) bool {
+	key = strings.ReplaceAll(strings.ToLower(key), "-", "_")
+	for _, sensitive := range sensitiveKeys {
+		if strings.Contains(key, sensitive) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// String masks email addresses found in free text
+func String(value string) string {
+	return emailPattern.ReplaceAllString(value, Mask)
+}
+
+// Query returns the encoded query with sensitive parameters masked
+func Query(values url.Values) string {
+	redacted := make(url.Values, len(values))
+	for key, items := range values {
+		for _, item := range items {
+			if IsSensitive(key) {
+				item = Mask
+			}
+			redacted.Add(key, String(item))
+		}
+	}
+
+	// Encode escapes the mask, keep it readable in the logs
+	return strings.ReplaceAll(redacted.Encode(), url.QueryEscape(Mask), Mask)
+}
+
+// JSON returns body with sensitive fields masked, ok is false when body is not JSON
+func JSON(body []byte) (string, bool) {
+	var value interface{}
+	if err := json.Unmarshal(body, &value); err != nil {
+		return "", false
+	}
+
+	redacted, err := json.Marshal(Value(value))
+	if err != nil {
+		return "", false
+	}
+
+	return string(redacted), true
+}
+
+// Value masks sensitive fields of a decoded JSON value recursively
+func Value(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		redacted := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			if IsSensitive(key) {
+				redacted[key] = Mask
+				continue
+			}
+			redacted[key] = Value(item)
+		}
+		return redacted
+	case []interface{}:
+		redacted := make([]interface{}, len(v))
+		for i, item := range v {
+			redacted[i] = Value(item)
+		}
+		return redacted
+	case string:
+		return String(v)
+	default:
+		return v
+	}
+}
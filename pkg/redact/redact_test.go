@@ -0,0 +1,151 @@
+package redact
+
+import (
+	"encoding/json"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestIsSensitive(t *testing.T) {
+	tests := []struct {
+		key  string
+		want bool
+	}{
+		{"password", true},
+		{"new_password", true},
+		{"Authorization", true},
+		{"refresh_token", true},
+		{"AccessToken", true},
+		{"verification_code", true},
+		{"client_secret", true},
+		{"email", true},
+		{"X-API-Key", true},
+		{"api_key", true},
+		{"first_name", false},
+		{"id", false},
+		{"page", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			if got := IsSensitive(tt.key); got != tt.want {
+				t.Errorf("IsSensitive(%q) = %v, want %v", tt.key, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestString(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"no such user john.doe+test@example.co.uk", "no such user [REDACTED]"},
+		{"a@b.io and c@d.uz", "[REDACTED] and [REDACTED]"},
+		{"user 42 not found", "user 42 not found"},
+		{"not@an-email", "not@an-email"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			if got := String(tt.value); got != tt.want {
+				t.Errorf("String(%q) = %q, want %q", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQuery(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{"sensitive parameters", "username=superadmin&password=superadminpass", "password=[REDACTED]&username=superadmin"},
+		{"every value of a parameter", "code=1&code=2&page=1", "code=[REDACTED]&code=[REDACTED]&page=1"},
+		{"email in a value", "q=ann@example.com", "q=[REDACTED]"},
+		{"nothing to mask", "limit=10&page=2", "limit=10&page=2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values, err := url.ParseQuery(tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := Query(values); got != tt.want {
+				t.Errorf("Query(%q) = %q, want %q", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{
+			name: "top level fields",
+			body: `{"email":"ann@example.com","password":"secret","first_name":"Ann"}`,
+			want: `{"email":"[REDACTED]","password":"[REDACTED]","first_name":"Ann"}`,
+		},
+		{
+			name: "nested objects",
+			body: `{"admin":{"id":"1","credentials":{"password":"p","api_key":"k"}}}`,
+			want: `{"admin":{"id":"1","credentials":{"password":"[REDACTED]","api_key":"[REDACTED]"}}}`,
+		},
+		{
+			name: "objects in arrays",
+			body: `{"users":[{"id":1,"refresh_token":"t"},{"id":2,"refresh_token":"u"}]}`,
+			want: `{"users":[{"id":1,"refresh_token":"[REDACTED]"},{"id":2,"refresh_token":"[REDACTED]"}]}`,
+		},
+		{
+			name: "sensitive key holding an object",
+			body: `{"secret":{"nested":"value"}}`,
+			want: `{"secret":"[REDACTED]"}`,
+		},
+		{
+			name: "email in free text",
+			body: `{"note":"contact ann@example.com","tags":["bob@example.com"]}`,
+			want: `{"note":"contact [REDACTED]","tags":["[REDACTED]"]}`,
+		},
+		{
+			name: "scalars are kept",
+			body: `{"age":30,"is_verified":true,"department_id":null}`,
+			want: `{"age":30,"is_verified":true,"department_id":null}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := JSON([]byte(tt.body))
+			if !ok {
+				t.Fatalf("JSON(%s) is not ok", tt.body)
+			}
+			if !jsonEqual(t, got, tt.want) {
+				t.Errorf("JSON(%s) = %s, want %s", tt.body, got, tt.want)
+			}
+		})
+	}
+
+	if _, ok := JSON([]byte("password=secret")); ok {
+		t.Error("JSON() accepted a body that is not JSON")
+	}
+}
+
+func jsonEqual(t *testing.T, a, b string) bool {
+	t.Helper()
+
+	var x, y interface{}
+	if err := json.Unmarshal([]byte(a), &x); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal([]byte(b), &y); err != nil {
+		t.Fatal(err)
+	}
+
+	return reflect.DeepEqual(x, y)
+}
@@ -0,0 +1,35 @@
+package requestid
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// Header is the HTTP header a request id is accepted from and returned in
+	Header = "X-Request-ID"
+	// MetadataKey is the gRPC metadata key a request id is propagated with
+	MetadataKey = "x-request-id"
+)
+
+type ctxKey struct{}
+
+// New generates a new request id
+func New() string {
+	return uuid.NewString()
+}
+
+// NewContext returns a copy of ctx carrying the request id
+func NewContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, ctxKey{}, id)
+}
+
+// FromContext returns the request id stored in ctx, or an empty string
+func FromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	id, _ := ctx.Value(ctxKey{}).(string)
+	return id
+}
@@ -0,0 +1,218 @@
+package tlsutil
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"myproject/admin-api-gateway/pkg/logger"
+	"os"
+	"sync"
+	"time"
+)
+
+// Reloader keeps a certificate pair and a CA bundle in memory and reads them
+// again whenever one of the files changes on disk, so rotated certificates
+// are picked up without a restart. Any of the files may be left empty.
+type Reloader struct {
+	certFile string
+	keyFile  string
+	caFile   string
+	log      logger.Logger
+
+	mu       sync.RWMutex
+	cert     *tls.Certificate
+	pool     *x509.CertPool
+	modTimes map[string]time.Time
+}
+
+// NewReloader loads the files once and fails if they cannot be used
+func NewReloader(certFile, keyFile, caFile string, log logger.Logger) (*Reloader, error) {
+	if (certFile == "") != (keyFile == "") {
+		return nil, errors.New("certificate and key files should be set together")
+	}
+
+	r := &Reloader{
+		certFile: certFile,
+		keyFile:  keyFile,
+		caFile:   caFile,
+		log:      log,
+		modTimes: make(map[string]time.Time),
+	}
+	if err := r.load(); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// Run polls the files every interval until ctx is done. A broken file is
+// logged and the previously loaded certificates stay in use.
+func (r *Reloader) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if !r.changed() {
+			continue
+		}
+		if err := r.load(); err != nil {
+			// the files may be half written, forget their state to retry on the next tick
+			r.modTimes = make(map[string]time.Time)
+			r.log.Error("cannot reload certificates, keeping the previous ones", logger.Error(err))
+			continue
+		}
+		r.log.Info("certificates are reloaded", logger.String("cert", r.certFile), logger.String("ca", r.caFile))
+	}
+}
+
+// Certificate returns the current certificate pair, nil if none is configured
+func (r *Reloader) Certificate() *tls.Certificate {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.cert
+}
+
+// CertPool returns the current CA bundle, nil if none is configured
+func (r *Reloader) CertPool() *x509.CertPool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.pool
+}
+
+// ClientConfig returns a TLS config for outgoing connections. The client
+// certificate is presented when the server asks for one, the server is
+// verified against the CA bundle or the system roots if there is none.
+func (r *Reloader) ClientConfig(serverName string) *tls.Config {
+	config := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: serverName,
+		GetClientCertificate: func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			if cert := r.Certificate(); cert != nil {
+				return cert, nil
+			}
+			return &tls.Certificate{}, nil
+		},
+	}
+
+	if r.caFile != "" {
+		// the default verification would pin the pool loaded at dial time,
+		// verify by hand so that a rotated CA bundle applies to new handshakes
+		config.InsecureSkipVerify = true
+		config.VerifyConnection = func(state tls.ConnectionState) error {
+			return r.verify(state.PeerCertificates, state.ServerName, x509.ExtKeyUsageServerAuth)
+		}
+	}
+
+	return config
+}
+
+// ServerConfig returns a TLS config for incoming connections that serves the
+// current certificate. With a CA bundle, client certificates are requested
+// and verified against it but not required.
+func (r *Reloader) ServerConfig() *tls.Config {
+	config := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		NextProtos: []string{"h2", "http/1.1"},
+		GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+			if cert := r.Certificate(); cert != nil {
+				return cert, nil
+			}
+			return nil, errors.New("no server certificate configured")
+		},
+	}
+
+	if r.caFile != "" {
+		config.GetConfigForClient = func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			clientConfig := config.Clone()
+			clientConfig.GetConfigForClient = nil
+			clientConfig.ClientCAs = r.CertPool()
+			clientConfig.ClientAuth = tls.VerifyClientCertIfGiven
+			return clientConfig, nil
+		}
+	}
+
+	return config
+}
+
+func (r *Reloader) verify(certs []*x509.Certificate, serverName string, usage x509.ExtKeyUsage) error {
+	if len(certs) == 0 {
+		return errors.New("no peer certificate presented")
+	}
+
+	intermediates := x509.NewCertPool()
+	for _, cert := range certs[1:] {
+		intermediates.AddCert(cert)
+	}
+
+	_, err := certs[0].Verify(x509.VerifyOptions{
+		Roots:         r.CertPool(),
+		Intermediates: intermediates,
+		DNSName:       serverName,
+		KeyUsages:     []x509.ExtKeyUsage{usage},
+	})
+
+	return err
+}
+
+func (r *Reloader) load() error {
+	var (
+		cert *tls.Certificate
+		pool *x509.CertPool
+	)
+
+	if r.certFile != "" {
+		pair, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+		if err != nil {
+			return fmt.Errorf("cannot load certificate %s: %w", r.certFile, err)
+		}
+		cert = &pair
+	}
+
+	if r.caFile != "" {
+		bundle, err := os.ReadFile(r.caFile)
+		if err != nil {
+			return fmt.Errorf("cannot read CA bundle: %w", err)
+		}
+		pool = x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(bundle) {
+			return fmt.Errorf("no certificates found in CA bundle %s", r.caFile)
+		}
+	}
+
+	r.mu.Lock()
+	r.cert, r.pool = cert, pool
+	r.mu.Unlock()
+
+	r.changed()
+	return nil
+}
+
+// changed reports whether any file was modified since the last call
+func (r *Reloader) changed() bool {
+	changed := false
+	for _, file := range []string{r.certFile, r.keyFile, r.caFile} {
+		if file == "" {
+			continue
+		}
+		info, err := os.Stat(file)
+		if err != nil {
+			continue
+		}
+		if !info.ModTime().Equal(r.modTimes[file]) {
+			r.modTimes[file] = info.ModTime()
+			changed = true
+		}
+	}
+
+	return changed
+}
@@ -0,0 +1,279 @@
+package tlsutil
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"myproject/admin-api-gateway/pkg/logger"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+type authority struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+func newAuthority(t *testing.T, name string) *authority {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &authority{cert: cert, key: key, pem: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})}
+}
+
+// issue signs a leaf certificate for usage and returns its PEM pair
+func (a *authority) issue(t *testing.T, name string, usage x509.ExtKeyUsage) ([]byte, []byte) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		DNSNames:     []string{name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, a.cert, &key.PublicKey, a.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+// writeFile replaces the file and moves its modification time forward, so
+// that a rewrite within the timestamp resolution still counts as a change
+func writeFile(t *testing.T, path string, data []byte) {
+	t.Helper()
+
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	modTime := time.Now().Add(time.Second)
+	if info, err := os.Stat(path); err == nil && !info.ModTime().Before(modTime) {
+		modTime = info.ModTime().Add(time.Second)
+	}
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+}
+
+type files struct {
+	cert, key, ca string
+}
+
+func writeFiles(t *testing.T, dir, prefix string, cert, key, ca []byte) files {
+	t.Helper()
+
+	f := files{
+		cert: filepath.Join(dir, prefix+".pem"),
+		key:  filepath.Join(dir, prefix+"-key.pem"),
+		ca:   filepath.Join(dir, prefix+"-ca.pem"),
+	}
+	writeFile(t, f.cert, cert)
+	writeFile(t, f.key, key)
+	writeFile(t, f.ca, ca)
+
+	return f
+}
+
+func newReloader(t *testing.T, f files) *Reloader {
+	t.Helper()
+
+	reloader, err := NewReloader(f.cert, f.key, f.ca, logger.New("error", "tlsutil_test"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return reloader
+}
+
+// serve starts a gRPC health server that requires client certificates
+// signed by the CA bundle of server
+func serve(t *testing.T, server *Reloader) *bufconn.Listener {
+	t.Helper()
+
+	config := server.ServerConfig()
+	perClient := config.GetConfigForClient
+	config.GetConfigForClient = func(hello *tls.ClientHelloInfo) (*tls.Config, error) {
+		clientConfig, err := perClient(hello)
+		if err != nil {
+			return nil, err
+		}
+		clientConfig.ClientAuth = tls.RequireAndVerifyClientCert
+		return clientConfig, nil
+	}
+
+	listener := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer(grpc.Creds(credentials.NewTLS(config)))
+	healthpb.RegisterHealthServer(srv, health.NewServer())
+	go srv.Serve(listener)
+	t.Cleanup(srv.Stop)
+
+	return listener
+}
+
+// check dials a new connection, so every call makes a new handshake
+func check(listener *bufconn.Listener, client *Reloader, serverName string) error {
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(credentials.NewTLS(client.ClientConfig(serverName))),
+	)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err = healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{})
+	return err
+}
+
+func TestHandshake(t *testing.T) {
+	dir := t.TempDir()
+	ca := newAuthority(t, "test-ca")
+	serverCert, serverKey := ca.issue(t, "healthcare-service", x509.ExtKeyUsageServerAuth)
+	clientCert, clientKey := ca.issue(t, "admin-api-gateway", x509.ExtKeyUsageClientAuth)
+
+	listener := serve(t, newReloader(t, writeFiles(t, dir, "server", serverCert, serverKey, ca.pem)))
+	client := newReloader(t, writeFiles(t, dir, "client", clientCert, clientKey, ca.pem))
+
+	if err := check(listener, client, "healthcare-service"); err != nil {
+		t.Fatalf("handshake with the client certificate failed: %v", err)
+	}
+
+	if err := check(listener, client, "user-service"); err == nil {
+		t.Error("handshake with a wrong server name succeeded")
+	}
+
+	other := newAuthority(t, "other-ca")
+	wrongCA := newReloader(t, writeFiles(t, dir, "wrong-ca", clientCert, clientKey, other.pem))
+	if err := check(listener, wrongCA, "healthcare-service"); err == nil {
+		t.Error("handshake trusting a wrong CA succeeded")
+	}
+
+	otherCert, otherKey := other.issue(t, "admin-api-gateway", x509.ExtKeyUsageClientAuth)
+	untrusted := newReloader(t, writeFiles(t, dir, "untrusted", otherCert, otherKey, ca.pem))
+	if err := check(listener, untrusted, "healthcare-service"); err == nil {
+		t.Error("handshake with a client certificate of a wrong CA succeeded")
+	}
+
+	anonymous, err := NewReloader("", "", writeFiles(t, dir, "anonymous", clientCert, clientKey, ca.pem).ca, logger.New("error", "tlsutil_test"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := check(listener, anonymous, "healthcare-service"); err == nil {
+		t.Error("handshake without a client certificate succeeded")
+	}
+}
+
+func TestRotation(t *testing.T) {
+	dir := t.TempDir()
+	oldCA := newAuthority(t, "old-ca")
+	serverCert, serverKey := oldCA.issue(t, "healthcare-service", x509.ExtKeyUsageServerAuth)
+	clientCert, clientKey := oldCA.issue(t, "admin-api-gateway", x509.ExtKeyUsageClientAuth)
+
+	serverFiles := writeFiles(t, dir, "server", serverCert, serverKey, oldCA.pem)
+	clientFiles := writeFiles(t, dir, "client", clientCert, clientKey, oldCA.pem)
+	server, client := newReloader(t, serverFiles), newReloader(t, clientFiles)
+	listener := serve(t, server)
+
+	if err := check(listener, client, "healthcare-service"); err != nil {
+		t.Fatalf("handshake before the rotation failed: %v", err)
+	}
+	oldServerDER := server.Certificate().Certificate[0]
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go server.Run(ctx, 10*time.Millisecond)
+	go client.Run(ctx, 10*time.Millisecond)
+
+	// the server moves to a certificate of a new CA, the client trusts
+	// only the new CA from now on
+	newCA := newAuthority(t, "new-ca")
+	serverCert, serverKey = newCA.issue(t, "healthcare-service", x509.ExtKeyUsageServerAuth)
+	writeFile(t, serverFiles.cert, serverCert)
+	writeFile(t, serverFiles.key, serverKey)
+	writeFile(t, clientFiles.ca, newCA.pem)
+
+	newPool := mustPool(t, newCA.pem)
+	deadline := time.Now().Add(5 * time.Second)
+	for !client.CertPool().Equal(newPool) || bytes.Equal(server.Certificate().Certificate[0], oldServerDER) {
+		if time.Now().After(deadline) {
+			t.Fatal("the rotated files were not reloaded")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if err := check(listener, client, "healthcare-service"); err != nil {
+		t.Fatalf("handshake after the rotation failed: %v", err)
+	}
+
+	stale := newReloader(t, writeFiles(t, dir, "stale", clientCert, clientKey, oldCA.pem))
+	if err := check(listener, stale, "healthcare-service"); err == nil {
+		t.Error("handshake trusting the old CA succeeded after the rotation")
+	}
+}
+
+func mustPool(t *testing.T, bundle []byte) *x509.CertPool {
+	t.Helper()
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(bundle) {
+		t.Fatal("invalid CA bundle")
+	}
+
+	return pool
+}
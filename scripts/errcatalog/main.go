@@ -0,0 +1,77 @@
+// Command errcatalog generates the error catalog of the API from the error
+// code constants of a Go file. Every constant documented with a trailing
+// comment of the form "// <http status> <description>" becomes an entry.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+)
+
+type entry struct {
+	name        string
+	status      int
+	description string
+}
+
+func main() {
+	in := flag.String("in", "handler.go", "file declaring the error codes")
+	out := flag.String("out", "error_catalog.go", "file to generate")
+	flag.Parse()
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, *in, nil, parser.ParseComments)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var entries []entry
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.CONST {
+			continue
+		}
+
+		for _, spec := range gen.Specs {
+			value := spec.(*ast.ValueSpec)
+			if value.Comment == nil || len(value.Names) != 1 {
+				continue
+			}
+
+			status, description, ok := strings.Cut(strings.TrimSpace(value.Comment.Text()), " ")
+			code, err := strconv.Atoi(status)
+			if !ok || err != nil {
+				log.Fatalf("%s: comment should be '<http status> <description>'", value.Names[0].Name)
+			}
+			entries = append(entries, entry{value.Names[0].Name, code, strings.TrimSpace(description)})
+		}
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by scripts/errcatalog from %s; DO NOT EDIT.\n\n", *in)
+	fmt.Fprintf(&buf, "package %s\n\n", file.Name.Name)
+	fmt.Fprintf(&buf, "import \"myproject/admin-api-gateway/api/models\"\n\n")
+	fmt.Fprintf(&buf, "// ErrorCatalog lists every error code the API responds with\n")
+	fmt.Fprintf(&buf, "var ErrorCatalog = []models.ErrorCatalogEntry{\n")
+	for _, e := range entries {
+		fmt.Fprintf(&buf, "\t{Code: %s, HTTPStatus: %d, Description: %q},\n", e.name, e.status, e.description)
+	}
+	fmt.Fprintf(&buf, "}\n")
+
+	source, err := format.Source(buf.Bytes())
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(*out, source, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
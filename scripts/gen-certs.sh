@@ -0,0 +1,32 @@
+#!/bin/bash
+# Generates a self-signed CA with a server and a client certificate for
+# running the gateway and the backend services over mTLS locally.
+set -e
+
+CERTS_DIR=${1:-$(pwd)/certs}
+SERVER_NAMES=${SERVER_NAMES:-"localhost,user-service,healthcare-service,admin-api-gateway"}
+DAYS=${DAYS:-365}
+
+mkdir -p $CERTS_DIR
+cd $CERTS_DIR
+
+SAN="IP:127.0.0.1"
+for name in ${SERVER_NAMES//,/ }; do
+    SAN="$SAN,DNS:$name"
+done
+
+openssl req -x509 -newkey rsa:4096 -nodes -days $DAYS \
+    -keyout ca-key.pem -out ca.pem -subj "/CN=clinic-dev-ca"
+
+openssl req -newkey rsa:2048 -nodes \
+    -keyout server-key.pem -out server.csr -subj "/CN=localhost"
+openssl x509 -req -in server.csr -CA ca.pem -CAkey ca-key.pem -CAcreateserial -days $DAYS \
+    -out server.pem -extfile <(printf "subjectAltName=$SAN\nextendedKeyUsage=serverAuth")
+
+openssl req -newkey rsa:2048 -nodes \
+    -keyout client-key.pem -out client.csr -subj "/CN=admin-api-gateway"
+openssl x509 -req -in client.csr -CA ca.pem -CAkey ca-key.pem -CAcreateserial -days $DAYS \
+    -out client.pem -extfile <(printf "extendedKeyUsage=clientAuth")
+
+rm -f server.csr client.csr ca.srl
+echo "certificates are written to $CERTS_DIR"
@@ -0,0 +1,150 @@
+package services
+
+import (
+	"context"
+	"myproject/admin-api-gateway/pkg/metrics"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+type breakerState int
+
+const (
+	breakerClosed breakerState = iota
+	breakerHalfOpen
+	breakerOpen
+)
+
+func (s breakerState) String() string {
+	switch s {
+	case breakerHalfOpen:
+		return "half-open"
+	case breakerOpen:
+		return "open"
+	}
+
+	return "closed"
+}
+
+// circuitBreaker stops calling a backend after a run of consecutive failures.
+// Once openTimeout passes a single trial call is let through, its result
+// either closes the breaker again or keeps it open for another period.
+type circuitBreaker struct {
+	service     string
+	threshold   int
+	openTimeout time.Duration
+
+	mu       sync.Mutex
+	state    breakerState
+	failures int
+	openedAt time.Time
+	trial    bool
+}
+
+func newCircuitBreaker(service string, threshold int, openTimeout time.Duration) *circuitBreaker {
+	b := &circuitBreaker{
+		service:     service,
+		threshold:   threshold,
+		openTimeout: openTimeout,
+	}
+	metrics.GRPCCircuitBreakerState.WithLabelValues(service).Set(float64(breakerClosed))
+
+	return b
+}
+
+// State returns the current state, an open breaker whose timeout passed is half-open
+func (b *circuitBreaker) State() breakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == breakerOpen && time.Since(b.openedAt) >= b.openTimeout {
+		return breakerHalfOpen
+	}
+
+	return b.state
+}
+
+func (b *circuitBreaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case breakerOpen:
+		if time.Since(b.openedAt) < b.openTimeout {
+			return false
+		}
+		b.setState(breakerHalfOpen)
+		fallthrough
+	case breakerHalfOpen:
+		if b.trial {
+			return false
+		}
+		b.trial = true
+	}
+
+	return true
+}
+
+func (b *circuitBreaker) record(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case breakerOpen:
+		// calls started before the breaker opened do not change its state
+		return
+	case breakerHalfOpen:
+		b.trial = false
+	}
+
+	if !isBackendFailure(err) {
+		b.failures = 0
+		b.setState(breakerClosed)
+		return
+	}
+
+	b.failures++
+	if b.state == breakerHalfOpen || b.failures >= b.threshold {
+		b.openedAt = time.Now()
+		b.setState(breakerOpen)
+	}
+}
+
+func (b *circuitBreaker) setState(state breakerState) {
+	b.state = state
+	metrics.GRPCCircuitBreakerState.WithLabelValues(b.service).Set(float64(state))
+}
+
+// unaryInterceptor rejects calls with Unavailable while the breaker is open,
+// so handlers answer 503 without waiting for the backend
+func (b *circuitBreaker) unaryInterceptor(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	// probes must not reset or trip the breaker, readiness reports it separately
+	if b.threshold <= 0 || method == healthpb.Health_Check_FullMethodName {
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+
+	if !b.allow() {
+		return status.Errorf(codes.Unavailable, "circuit breaker is open for the %s service", b.service)
+	}
+
+	err := invoker(ctx, method, req, reply, cc, opts...)
+	b.record(err)
+
+	return err
+}
+
+// isBackendFailure tells apart errors caused by the backend being unhealthy
+// from regular application errors such as NotFound or InvalidArgument
+func isBackendFailure(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted, codes.Internal:
+		return true
+	}
+
+	return false
+}
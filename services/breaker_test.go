@@ -0,0 +1,207 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+const testMethod = "/healthcare.HealthCareService/GetDoctorById"
+
+// call is one call through the breaker, err is what the backend answers
+type call struct {
+	method     string
+	err        error
+	wantCalled bool
+	wantState  breakerState
+}
+
+var (
+	unavailable = status.Error(codes.Unavailable, "connection refused")
+	timeout     = status.Error(codes.DeadlineExceeded, "deadline exceeded")
+	notFound    = status.Error(codes.NotFound, "doctor not found")
+)
+
+func TestBreakerTransitions(t *testing.T) {
+	tests := []struct {
+		name        string
+		threshold   int
+		openTimeout time.Duration
+		calls       []call
+	}{
+		{
+			name:        "opens after consecutive failures",
+			threshold:   3,
+			openTimeout: time.Hour,
+			calls: []call{
+				{err: unavailable, wantCalled: true, wantState: breakerClosed},
+				{err: timeout, wantCalled: true, wantState: breakerClosed},
+				{err: unavailable, wantCalled: true, wantState: breakerOpen},
+				{err: nil, wantCalled: false, wantState: breakerOpen},
+			},
+		},
+		{
+			name:        "a success resets the failures",
+			threshold:   3,
+			openTimeout: time.Hour,
+			calls: []call{
+				{err: unavailable, wantCalled: true, wantState: breakerClosed},
+				{err: unavailable, wantCalled: true, wantState: breakerClosed},
+				{err: nil, wantCalled: true, wantState: breakerClosed},
+				{err: unavailable, wantCalled: true, wantState: breakerClosed},
+				{err: unavailable, wantCalled: true, wantState: breakerClosed},
+			},
+		},
+		{
+			name:        "application errors are no failures",
+			threshold:   2,
+			openTimeout: time.Hour,
+			calls: []call{
+				{err: notFound, wantCalled: true, wantState: breakerClosed},
+				{err: status.Error(codes.InvalidArgument, "bad email"), wantCalled: true, wantState: breakerClosed},
+				{err: notFound, wantCalled: true, wantState: breakerClosed},
+			},
+		},
+		{
+			name:        "a successful trial closes it",
+			threshold:   1,
+			openTimeout: 0,
+			calls: []call{
+				{err: unavailable, wantCalled: true, wantState: breakerHalfOpen},
+				{err: nil, wantCalled: true, wantState: breakerClosed},
+				{err: nil, wantCalled: true, wantState: breakerClosed},
+			},
+		},
+		{
+			name:        "a failed trial needs another trial",
+			threshold:   2,
+			openTimeout: 0,
+			calls: []call{
+				{err: unavailable, wantCalled: true, wantState: breakerClosed},
+				{err: unavailable, wantCalled: true, wantState: breakerHalfOpen},
+				{err: status.Error(codes.Internal, "panic"), wantCalled: true, wantState: breakerHalfOpen},
+				{err: nil, wantCalled: true, wantState: breakerClosed},
+			},
+		},
+		{
+			name:        "an application error of the trial closes it",
+			threshold:   1,
+			openTimeout: 0,
+			calls: []call{
+				{err: unavailable, wantCalled: true, wantState: breakerHalfOpen},
+				{err: notFound, wantCalled: true, wantState: breakerClosed},
+			},
+		},
+		{
+			name:        "health checks pass an open breaker",
+			threshold:   1,
+			openTimeout: time.Hour,
+			calls: []call{
+				{err: unavailable, wantCalled: true, wantState: breakerOpen},
+				{method: healthpb.Health_Check_FullMethodName, err: nil, wantCalled: true, wantState: breakerOpen},
+				{err: nil, wantCalled: false, wantState: breakerOpen},
+			},
+		},
+		{
+			name:        "disabled without a threshold",
+			threshold:   0,
+			openTimeout: time.Hour,
+			calls: []call{
+				{err: unavailable, wantCalled: true, wantState: breakerClosed},
+				{err: unavailable, wantCalled: true, wantState: breakerClosed},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := newCircuitBreaker("healthcare", tt.threshold, tt.openTimeout)
+
+			for i, c := range tt.calls {
+				method := c.method
+				if method == "" {
+					method = testMethod
+				}
+
+				called := false
+				err := b.unaryInterceptor(context.Background(), method, nil, nil, nil,
+					func(context.Context, string, interface{}, interface{}, *grpc.ClientConn, ...grpc.CallOption) error {
+						called = true
+						return c.err
+					})
+
+				if called != c.wantCalled {
+					t.Errorf("call %d reached the backend = %v, want %v", i, called, c.wantCalled)
+				}
+				if !called && status.Code(err) != codes.Unavailable {
+					t.Errorf("call %d rejected with %v, want Unavailable", i, err)
+				}
+				if state := b.State(); state != c.wantState {
+					t.Errorf("state after call %d = %s, want %s", i, state, c.wantState)
+				}
+			}
+		})
+	}
+}
+
+func TestBreakerSingleTrial(t *testing.T) {
+	b := newCircuitBreaker("user", 1, 0)
+	if !b.allow() {
+		t.Fatal("closed breaker rejected a call")
+	}
+	b.record(unavailable)
+
+	if !b.allow() {
+		t.Fatal("half-open breaker rejected the trial call")
+	}
+	if b.allow() {
+		t.Error("half-open breaker let a second call through while the trial runs")
+	}
+
+	b.record(nil)
+	if !b.allow() || !b.allow() {
+		t.Error("breaker closed by the trial rejected calls")
+	}
+}
+
+func TestBreakerFailedTrialRestartsTimeout(t *testing.T) {
+	b := newCircuitBreaker("user", 1, time.Hour)
+	b.allow()
+	b.record(unavailable)
+
+	// the open timeout passed
+	b.openedAt = time.Now().Add(-2 * time.Hour)
+	if state := b.State(); state != breakerHalfOpen {
+		t.Fatalf("state after the timeout = %s, want half-open", state)
+	}
+	if !b.allow() {
+		t.Fatal("half-open breaker rejected the trial call")
+	}
+	b.record(timeout)
+
+	if state := b.State(); state != breakerOpen {
+		t.Errorf("state after a failed trial = %s, want open for another period", state)
+	}
+	if b.allow() {
+		t.Error("breaker let a call through right after a failed trial")
+	}
+}
+
+func TestBreakerIgnoresCallsFromBeforeOpening(t *testing.T) {
+	b := newCircuitBreaker("user", 1, time.Hour)
+
+	// both calls are let through while the breaker is closed
+	b.allow()
+	b.allow()
+	b.record(unavailable)
+	b.record(nil)
+
+	if state := b.State(); state != breakerOpen {
+		t.Errorf("state = %s, want a late success to leave it open", state)
+	}
+}
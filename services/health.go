@@ -0,0 +1,70 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/connectivity"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+// Backends returns the names of the backend services in dial order
+func (s *serviceManager) Backends() []string {
+	names := make([]string, 0, len(s.conns))
+	for _, backend := range s.conns {
+		names = append(names, backend.name)
+	}
+
+	return names
+}
+
+// Ping asks the backend for its status through the gRPC health protocol.
+// Backends that do not implement it are judged by the connectivity state
+// of their connection. An open circuit breaker fails the check right away.
+func (s *serviceManager) Ping(ctx context.Context, backend string) error {
+	for _, b := range s.conns {
+		if b.name != backend {
+			continue
+		}
+
+		if state := b.breaker.State(); state == breakerOpen {
+			return fmt.Errorf("%s service circuit breaker is %s", backend, state)
+		}
+
+		resp, err := healthpb.NewHealthClient(b.conn).Check(ctx, &healthpb.HealthCheckRequest{})
+		if status.Code(err) == codes.Unimplemented {
+			return waitForReady(ctx, b.conn)
+		}
+		if err != nil {
+			return err
+		}
+		if resp.GetStatus() != healthpb.HealthCheckResponse_SERVING {
+			return fmt.Errorf("%s service is %s", backend, resp.GetStatus())
+		}
+
+		return nil
+	}
+
+	return fmt.Errorf("unknown backend %q", backend)
+}
+
+func waitForReady(ctx context.Context, conn *grpc.ClientConn) error {
+	for {
+		state := conn.GetState()
+		switch state {
+		case connectivity.Ready:
+			return nil
+		case connectivity.Idle:
+			conn.Connect()
+		case connectivity.Shutdown:
+			return fmt.Errorf("connection is shut down")
+		}
+
+		if !conn.WaitForStateChange(ctx, state) {
+			return fmt.Errorf("connection is %s: %w", state, ctx.Err())
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package services
+
+import (
+	"context"
+	"myproject/admin-api-gateway/pkg/metrics"
+	"myproject/admin-api-gateway/pkg/requestid"
+	"path"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// requestIDUnaryInterceptor forwards the request id of the incoming HTTP request
+// to the backend services as outgoing gRPC metadata.
+func requestIDUnaryInterceptor(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	return invoker(withRequestID(ctx), method, req, reply, cc, opts...)
+}
+
+func requestIDStreamInterceptor(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	return streamer(withRequestID(ctx), desc, cc, method, opts...)
+}
+
+func withRequestID(ctx context.Context) context.Context {
+	id := requestid.FromContext(ctx)
+	if id == "" {
+		return ctx
+	}
+
+	return metadata.AppendToOutgoingContext(ctx, requestid.MetadataKey, id)
+}
+
+// metricsUnaryInterceptor records call counters and latency per method of the given backend
+func metricsUnaryInterceptor(service string) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		start := time.Now()
+		err := invoker(ctx, method, req, reply, cc, opts...)
+
+		name := path.Base(method)
+		metrics.GRPCClientRequests.WithLabelValues(service, name, status.Code(err).String()).Inc()
+		metrics.GRPCClientDuration.WithLabelValues(service, name).Observe(time.Since(start).Seconds())
+		return err
+	}
+}
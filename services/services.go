@@ -1,24 +1,59 @@
 package services
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"myproject/admin-api-gateway/config"
 	pbh "myproject/admin-api-gateway/genproto/healthcare-service"
 	pbu "myproject/admin-api-gateway/genproto/user-service"
+	"myproject/admin-api-gateway/pkg/logger"
+	"myproject/admin-api-gateway/pkg/tlsutil"
+	"time"
 
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/backoff"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/keepalive"
 	"google.golang.org/grpc/resolver"
 )
 
+// idempotentMethods are safe to retry, they only read data from the backends
+var idempotentMethods = map[string][]string{
+	"user.UserService": {
+		"GetUserById", "GetAllUsers", "CheckField", "IfExists",
+	},
+	"healthcare.HealthcareService": {
+		"GetDoctorById", "GetAllDoctors", "GetAllDoctorsByDepartmentId",
+		"GetDepartmentById", "GetAllDepartments",
+		"GetSpecializationById", "GetAllSpecializations", "GetAllSpecByDepartmentIdWithPrices",
+		"GetSpecPriceById", "GetAllSpecPrice",
+		"CheckUniques", "Exists", "IsVerified",
+	},
+}
+
 type IServiceManager interface {
 	UserService() pbu.UserServiceClient
 	HealthCareService() pbh.HealthcareServiceClient
+	Backends() []string
+	Ping(ctx context.Context, backend string) error
+	Close() error
 }
 
 type serviceManager struct {
 	userService       pbu.UserServiceClient
 	healthcareService pbh.HealthcareServiceClient
+	conns             []backendConn
+	stopReload        context.CancelFunc
+}
+
+type backendConn struct {
+	name    string
+	conn    *grpc.ClientConn
+	breaker *circuitBreaker
 }
 
 func (s *serviceManager) UserService() pbu.UserServiceClient {
@@ -29,22 +64,146 @@ func (s *serviceManager) HealthCareService() pbh.HealthcareServiceClient {
 	return s.healthcareService
 }
 
-func NewServiceManager(cfg config.Config) (IServiceManager, error) {
+// Close closes the connections to the backend services
+func (s *serviceManager) Close() error {
+	s.stopReload()
+
+	var errs []error
+	for _, backend := range s.conns {
+		if err := backend.conn.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func NewServiceManager(cfg config.Config, log logger.Logger) (IServiceManager, error) {
 	resolver.SetDefaultScheme("dns")
 
-	connUser, err := grpc.Dial(
-		fmt.Sprintf("%s:%d", cfg.UserServiceHost, cfg.UserServicePort),
-		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	// the certificate reloaders live as long as the connections
+	ctx, stopReload := context.WithCancel(context.Background())
+
+	user, err := dial(ctx, cfg, log, "user", cfg.UserServiceHost, cfg.UserServicePort, cfg.UserServiceTLS)
 	if err != nil {
+		stopReload()
 		return nil, fmt.Errorf("user service dial error, %s:%d:%v", cfg.UserServiceHost, cfg.UserServicePort, err)
 	}
 
-	connHealthcare, err := grpc.Dial(
-		fmt.Sprintf("%s:%d", cfg.HealthcareServiceHost, cfg.HealthcareServicePort),
-		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	healthcare, err := dial(ctx, cfg, log, "healthcare", cfg.HealthcareServiceHost, cfg.HealthcareServicePort, cfg.HealthcareServiceTLS)
+	if err != nil {
+		stopReload()
+		user.conn.Close()
+		return nil, fmt.Errorf("healthcare service dial error, %s:%d:%v", cfg.HealthcareServiceHost, cfg.HealthcareServicePort, err)
+	}
+
+	return &serviceManager{
+		userService:       pbu.NewUserServiceClient(user.conn),
+		healthcareService: pbh.NewHealthcareServiceClient(healthcare.conn),
+		conns:             []backendConn{user, healthcare},
+		stopReload:        stopReload,
+	}, nil
+}
+
+func dial(ctx context.Context, cfg config.Config, log logger.Logger, name, host string, port int, tlsCfg config.TLSConfig) (backendConn, error) {
+	serviceConfig, err := retryServiceConfig(cfg)
+	if err != nil {
+		return backendConn{}, err
+	}
+
+	creds, err := transportCredentials(ctx, cfg, log, tlsCfg)
+	if err != nil {
+		return backendConn{}, err
+	}
+
+	breaker := newCircuitBreaker(name, cfg.GRPCBreakerFailures, time.Second*time.Duration(cfg.GRPCBreakerOpenTimeout))
+
+	conn, err := grpc.Dial(
+		fmt.Sprintf("%s:%d", host, port),
+		grpc.WithTransportCredentials(creds),
+		grpc.WithDefaultServiceConfig(serviceConfig),
+		grpc.WithKeepaliveParams(keepalive.ClientParameters{
+			Time:                time.Second * time.Duration(cfg.GRPCKeepaliveTime),
+			Timeout:             time.Second * time.Duration(cfg.GRPCKeepaliveTimeout),
+			PermitWithoutStream: true,
+		}),
+		grpc.WithConnectParams(grpc.ConnectParams{
+			Backoff: backoff.Config{
+				BaseDelay:  backoff.DefaultConfig.BaseDelay,
+				Multiplier: backoff.DefaultConfig.Multiplier,
+				Jitter:     backoff.DefaultConfig.Jitter,
+				MaxDelay:   time.Second * time.Duration(cfg.GRPCConnectBackoffMax),
+			},
+			MinConnectTimeout: 5 * time.Second,
+		}),
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
+		grpc.WithChainUnaryInterceptor(requestIDUnaryInterceptor, metricsUnaryInterceptor(name), breaker.unaryInterceptor),
+		grpc.WithChainStreamInterceptor(requestIDStreamInterceptor))
+	if err != nil {
+		return backendConn{}, err
+	}
+
+	return backendConn{name: name, conn: conn, breaker: breaker}, nil
+}
+
+// transportCredentials returns TLS credentials when the backend has TLS enabled,
+// with a client certificate for mTLS if one is configured
+func transportCredentials(ctx context.Context, cfg config.Config, log logger.Logger, tlsCfg config.TLSConfig) (credentials.TransportCredentials, error) {
+	if !tlsCfg.Enabled {
+		return insecure.NewCredentials(), nil
+	}
+
+	reloader, err := tlsutil.NewReloader(tlsCfg.CertFile, tlsCfg.KeyFile, tlsCfg.CAFile, log)
 	if err != nil {
-		return nil, fmt.Errorf("healthcare service dial error, %s:%d:%v", cfg.UserServiceHost, cfg.UserServicePort, err)
+		return nil, err
+	}
+	go reloader.Run(ctx, time.Second*time.Duration(cfg.TLSReloadInterval))
+
+	return credentials.NewTLS(reloader.ClientConfig(tlsCfg.ServerName)), nil
+}
+
+// retryServiceConfig builds the gRPC service config that retries the
+// idempotent methods when a backend is briefly unavailable
+func retryServiceConfig(cfg config.Config) (string, error) {
+	type methodName struct {
+		Service string `json:"service"`
+		Method  string `json:"method"`
+	}
+	type retryPolicy struct {
+		MaxAttempts          int      `json:"maxAttempts"`
+		InitialBackoff       string   `json:"initialBackoff"`
+		MaxBackoff           string   `json:"maxBackoff"`
+		BackoffMultiplier    float64  `json:"backoffMultiplier"`
+		RetryableStatusCodes []string `json:"retryableStatusCodes"`
+	}
+	type methodConfig struct {
+		Name        []methodName `json:"name"`
+		RetryPolicy *retryPolicy `json:"retryPolicy,omitempty"`
 	}
 
-	return &serviceManager{userService: pbu.NewUserServiceClient(connUser), healthcareService: pbh.NewHealthcareServiceClient(connHealthcare)}, nil
+	if cfg.GRPCRetryMaxAttempts < 2 {
+		return "{}", nil
+	}
+
+	var names []methodName
+	for service, methods := range idempotentMethods {
+		for _, method := range methods {
+			names = append(names, methodName{Service: service, Method: method})
+		}
+	}
+
+	serviceConfig, err := json.Marshal(map[string][]methodConfig{
+		"methodConfig": {{
+			Name: names,
+			RetryPolicy: &retryPolicy{
+				MaxAttempts:          cfg.GRPCRetryMaxAttempts,
+				InitialBackoff:       fmt.Sprintf("%.3fs", (time.Millisecond * time.Duration(cfg.GRPCRetryInitialBackoff)).Seconds()),
+				MaxBackoff:           fmt.Sprintf("%.3fs", (time.Millisecond * time.Duration(cfg.GRPCRetryMaxBackoff)).Seconds()),
+				BackoffMultiplier:    2,
+				RetryableStatusCodes: []string{"UNAVAILABLE"},
+			},
+		}},
+	})
+
+	return string(serviceConfig), err
 }
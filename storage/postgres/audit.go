@@ -0,0 +1,223 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"myproject/admin-api-gateway/api/models"
+	"myproject/admin-api-gateway/pkg/tracing"
+	"strings"
+)
+
+const auditColumns = `id, created_at, actor_id, actor_role, action, resource_type, resource_id,
+				method, route, status, before, after, diff, ip, user_agent, request_id`
+
+type auditRepo struct {
+	db *sql.DB
+}
+
+func NewAuditRepo(db *sql.DB) *auditRepo {
+	return &auditRepo{db: db}
+}
+
+// Insert writes the entries with a single multi-row statement
+func (r *auditRepo) Insert(ctx context.Context, entries []*models.AuditEntry) (err error) {
+	ctx, span := startSpan(ctx, "INSERT audit_logs")
+	defer func() { tracing.End(span, err) }()
+
+	if len(entries) == 0 {
+		return nil
+	}
+
+	const columns = 15
+	var (
+		rows = make([]string, 0, len(entries))
+		args = make([]interface{}, 0, len(entries)*columns)
+	)
+	for i, entry := range entries {
+		placeholders := make([]string, columns)
+		for j := range placeholders {
+			placeholders[j] = fmt.Sprintf("$%d", i*columns+j+1)
+		}
+		rows = append(rows, "("+strings.Join(placeholders, ", ")+")")
+
+		args = append(args, entry.CreatedAt,
+			entry.ActorID,
+			entry.ActorRole,
+			entry.Action,
+			entry.ResourceType,
+			entry.ResourceID,
+			entry.Method,
+			entry.Route,
+			entry.Status,
+			jsonb(entry.Before),
+			jsonb(entry.After),
+			jsonb(entry.Diff),
+			entry.IP,
+			entry.UserAgent,
+			entry.RequestID)
+	}
+
+	query := `INSERT INTO audit_logs(created_at, actor_id, actor_role, action, resource_type, resource_id,
+				method, route, status, before, after, diff, ip, user_agent, request_id)
+				VALUES ` + strings.Join(rows, ", ")
+	_, err = r.db.ExecContext(ctx, query, args...)
+
+	return err
+}
+
+// List returns a page of the entries matching the filter, newest first
+func (r *auditRepo) List(ctx context.Context, filter models.AuditFilter) (_ *models.ListAuditEntries, err error) {
+	ctx, span := startSpan(ctx, "SELECT audit_logs")
+	defer func() { tracing.End(span, err) }()
+
+	where, args := auditWhere(filter)
+
+	var list models.ListAuditEntries
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(1) FROM audit_logs`+where, args...).Scan(&list.Count); err != nil {
+		return nil, err
+	}
+
+	args = append(args, filter.Limit, (filter.Page-1)*filter.Limit)
+	query := fmt.Sprintf(`SELECT %s FROM audit_logs%s ORDER BY created_at DESC, id DESC LIMIT $%d OFFSET $%d`,
+		auditColumns, where, len(args)-1, len(args))
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list.Entries = []*models.AuditEntry{}
+	for rows.Next() {
+		entry, err := scanAuditEntry(rows)
+		if err != nil {
+			return nil, err
+		}
+		list.Entries = append(list.Entries, entry)
+	}
+
+	return &list, rows.Err()
+}
+
+// Export streams every entry matching the filter to fn, newest first,
+// without holding them in memory
+func (r *auditRepo) Export(ctx context.Context, filter models.AuditFilter, fn func(*models.AuditEntry) error) (err error) {
+	ctx, span := startSpan(ctx, "SELECT audit_logs")
+	defer func() { tracing.End(span, err) }()
+
+	where, args := auditWhere(filter)
+	rows, err := r.db.QueryContext(ctx, `SELECT `+auditColumns+` FROM audit_logs`+where+` ORDER BY created_at DESC, id DESC`, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		entry, err := scanAuditEntry(rows)
+		if err != nil {
+			return err
+		}
+		if err := fn(entry); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+func auditWhere(filter models.AuditFilter) (string, []interface{}) {
+	var (
+		conditions []string
+		args       []interface{}
+	)
+	add := func(condition string, value interface{}) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if filter.ActorID != "" {
+		add("actor_id = $%d", filter.ActorID)
+	}
+	if filter.ActorRole != "" {
+		add("actor_role = $%d", filter.ActorRole)
+	}
+	if filter.Action != "" {
+		add("action = $%d", filter.Action)
+	}
+	if filter.ResourceType != "" {
+		add("resource_type = $%d", filter.ResourceType)
+	}
+	if filter.ResourceID != "" {
+		add("resource_id = $%d", filter.ResourceID)
+	}
+	if !filter.From.IsZero() {
+		add("created_at >= $%d", filter.From)
+	}
+	if !filter.To.IsZero() {
+		add("created_at < $%d", filter.To)
+	}
+
+	if len(conditions) == 0 {
+		return "", nil
+	}
+
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
+
+func scanAuditEntry(rows *sql.Rows) (*models.AuditEntry, error) {
+	var (
+		entry               models.AuditEntry
+		before, after, diff []byte
+	)
+	if err := rows.Scan(&entry.ID,
+		&entry.CreatedAt,
+		&entry.ActorID,
+		&entry.ActorRole,
+		&entry.Action,
+		&entry.ResourceType,
+		&entry.ResourceID,
+		&entry.Method,
+		&entry.Route,
+		&entry.Status,
+		&before,
+		&after,
+		&diff,
+		&entry.IP,
+		&entry.UserAgent,
+		&entry.RequestID); err != nil {
+		return nil, err
+	}
+
+	for _, column := range []struct {
+		raw  []byte
+		into interface{}
+	}{{before, &entry.Before}, {after, &entry.After}, {diff, &entry.Diff}} {
+		if len(column.raw) == 0 {
+			continue
+		}
+		if err := json.Unmarshal(column.raw, column.into); err != nil {
+			return nil, err
+		}
+	}
+
+	return &entry, nil
+}
+
+// jsonb encodes value for a JSONB column, nil values are stored as NULL
+func jsonb(value interface{}) interface{} {
+	if value == nil {
+		return nil
+	}
+	if diff, ok := value.(map[string]models.AuditChange); ok && len(diff) == 0 {
+		return nil
+	}
+
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return nil
+	}
+
+	return string(raw)
+}
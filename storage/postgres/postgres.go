@@ -6,8 +6,18 @@ import (
 	"errors"
 	"fmt"
 	"myproject/admin-api-gateway/api/models"
+	"myproject/admin-api-gateway/pkg/cursor"
+	"myproject/admin-api-gateway/pkg/listquery"
+	"myproject/admin-api-gateway/pkg/tracing"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
+var tracer = otel.Tracer("myproject/admin-api-gateway/storage/postgres")
+
 type adminRepo struct {
 	db *sql.DB
 }
@@ -16,10 +26,13 @@ func NewAdminRepo(db *sql.DB) *adminRepo {
 	return &adminRepo{db: db}
 }
 
-func (r *adminRepo) Create(ctx context.Context, admin *models.AdminResp) error {
+func (r *adminRepo) Create(ctx context.Context, admin *models.AdminResp) (err error) {
+	ctx, span := startSpan(ctx, "INSERT admins")
+	defer func() { tracing.End(span, err) }()
+
 	query := `INSERT INTO admins(id, full_name, age, username, email, password, role, refresh_token) 
 								VALUES($1, $2, $3, $4, $5, $6, $7, $8)`
-	_, err := r.db.Exec(query, admin.Id,
+	_, err = r.db.ExecContext(ctx, query, admin.Id,
 		admin.FullName,
 		admin.Age,
 		admin.UserName,
@@ -31,7 +44,10 @@ func (r *adminRepo) Create(ctx context.Context, admin *models.AdminResp) error {
 	return err
 }
 
-func (r *adminRepo) Update(ctx context.Context, adminReq *models.AdminUpdateReq) (*models.AdminReq, error) {
+func (r *adminRepo) Update(ctx context.Context, adminReq *models.AdminUpdateReq) (_ *models.AdminReq, err error) {
+	ctx, span := startSpan(ctx, "UPDATE admins")
+	defer func() { tracing.End(span, err) }()
+
 	query := `UPDATE admins SET 
 					full_name = $1, 
 					age = $2, 
@@ -43,8 +59,9 @@ func (r *adminRepo) Update(ctx context.Context, adminReq *models.AdminUpdateReq)
 							  username, 
 							  email, 
 							  password, 
-							  role`
-	row := r.db.QueryRow(query, adminReq.FullName, adminReq.Age, adminReq.UserName, adminReq.Id)
+							  role, 
+							  created_at`
+	row := r.db.QueryRowContext(ctx, query, adminReq.FullName, adminReq.Age, adminReq.UserName, adminReq.Id)
 
 	var admin models.AdminReq
 	if err := row.Scan(&admin.Id,
@@ -53,16 +70,20 @@ func (r *adminRepo) Update(ctx context.Context, adminReq *models.AdminUpdateReq)
 		&admin.UserName,
 		&admin.Email,
 		&admin.Password,
-		&admin.Role); err != nil {
+		&admin.Role,
+		&admin.CreatedAt); err != nil {
 		return nil, err
 	}
 
 	return &admin, nil
 }
 
-func (r *adminRepo) Delete(ctx context.Context, userName, password string) error {
+func (r *adminRepo) Delete(ctx context.Context, userName, password string) (err error) {
+	ctx, span := startSpan(ctx, "DELETE admins")
+	defer func() { tracing.End(span, err) }()
+
 	query := `DELETE FROM admins WHERE username = $1`
-	result, err := r.db.Exec(query, userName)
+	result, err := r.db.ExecContext(ctx, query, userName)
 	if err != nil {
 		return err
 	}
@@ -81,6 +102,9 @@ func (r *adminRepo) Delete(ctx context.Context, userName, password string) error
 }
 
 func (r *adminRepo) Check(ctx context.Context, userName string) (string, string, bool, error) {
+	ctx, span := startSpan(ctx, "SELECT admins")
+	defer span.End()
+
 	query := `SELECT COUNT(1), password, role
 	FROM admins GROUP by username, password, role
 	HAVING username = $1
@@ -88,7 +112,7 @@ func (r *adminRepo) Check(ctx context.Context, userName string) (string, string,
 	var status int
 	var password string
 	var role string
-	result := r.db.QueryRow(query, userName)
+	result := r.db.QueryRowContext(ctx, query, userName)
 	if err := result.Scan(&status, &password, &role); err != nil {
 		return "", "", false, nil
 	}
@@ -96,16 +120,20 @@ func (r *adminRepo) Check(ctx context.Context, userName string) (string, string,
 	return role, password, status == 1, nil
 }
 
-func (r *adminRepo) GetAdmin(rctx context.Context, req models.GetAdminReq) (*models.AdminReq, error) {
+func (r *adminRepo) GetAdmin(ctx context.Context, req models.GetAdminReq) (_ *models.AdminReq, err error) {
+	ctx, span := startSpan(ctx, "SELECT admins")
+	defer func() { tracing.End(span, err) }()
+
 	query := `SELECT id, 
 				full_name, 
 				age, 
 				username, 
 				email, 
 				password, 
-				role FROM admins WHERE id = $1`
+				role, 
+				created_at FROM admins WHERE id = $1`
 
-	row := r.db.QueryRow(query, req.Id)
+	row := r.db.QueryRowContext(ctx, query, req.Id)
 
 	var admin models.AdminReq
 	if err := row.Scan(&admin.Id,
@@ -114,28 +142,34 @@ func (r *adminRepo) GetAdmin(rctx context.Context, req models.GetAdminReq) (*mod
 		&admin.UserName,
 		&admin.Email,
 		&admin.Password,
-		&admin.Role); err != nil {
+		&admin.Role,
+		&admin.CreatedAt); err != nil {
 		return nil, err
 	}
 
 	return &admin, nil
 }
 
-func (r *adminRepo) ListAdmins(ctx context.Context, req models.ListAdminReq) (*models.ListAdminsResp, error) {
+func (r *adminRepo) ListAdmins(ctx context.Context, req models.ListAdminReq) (_ *models.ListAdminsResp, err error) {
+	ctx, span := startSpan(ctx, "SELECT admins")
+	defer func() { tracing.End(span, err) }()
+
 	query := `SELECT id, 
 				full_name, 
 				age, 
 				username, 
 				email, 
 				password, 
-				role FROM admins`
+				role, 
+				created_at FROM admins`
 
 	var admins models.ListAdminsResp
 
-	rows, err := r.db.Query(query)
+	rows, err := r.db.QueryContext(ctx, query)
 	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
 
 	for rows.Next() {
 		var admin models.AdminReq
@@ -145,7 +179,8 @@ func (r *adminRepo) ListAdmins(ctx context.Context, req models.ListAdminReq) (*m
 			&admin.UserName,
 			&admin.Email,
 			&admin.Password,
-			&admin.Role); err != nil {
+			&admin.Role,
+			&admin.CreatedAt); err != nil {
 			return nil, err
 		}
 		admins.Admins = append(admins.Admins, &admin)
@@ -154,3 +189,158 @@ func (r *adminRepo) ListAdmins(ctx context.Context, req models.ListAdminReq) (*m
 
 	return &admins, nil
 }
+
+// adminColumns maps the fields admins are filtered and sorted on to their
+// columns, only these ever reach the SQL text
+var adminColumns = map[string]string{
+	"full_name":  "full_name",
+	"username":   "username",
+	"email":      "email",
+	"age":        "age",
+	"role":       "role",
+	"created_at": "created_at",
+}
+
+// QueryAdmins returns a page of the admins matching the query and the
+// number of all matching admins
+func (r *adminRepo) QueryAdmins(ctx context.Context, query listquery.Query) (_ []*models.AdminReq, _ int64, err error) {
+	ctx, span := startSpan(ctx, "SELECT admins")
+	defer func() { tracing.End(span, err) }()
+
+	where, args := adminFilters(query)
+	var order []string
+	for _, key := range query.Sort {
+		column, ok := adminColumns[key.Field]
+		if !ok {
+			continue
+		}
+		if key.Desc {
+			column += " DESC"
+		}
+		order = append(order, column)
+	}
+	order = append(order, "id")
+
+	statement := `SELECT id, full_name, age, username, email, password, role, created_at, COUNT(*) OVER () FROM admins`
+	if len(where) > 0 {
+		statement += " WHERE " + strings.Join(where, " AND ")
+	}
+	args = append(args, query.Limit, query.Offset())
+	statement += fmt.Sprintf(" ORDER BY %s LIMIT $%d OFFSET $%d", strings.Join(order, ", "), len(args)-1, len(args))
+
+	rows, err := r.db.QueryContext(ctx, statement, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var (
+		admins []*models.AdminReq
+		total  int64
+	)
+	for rows.Next() {
+		var (
+			admin models.AdminReq
+			age   sql.NullInt64
+		)
+		if err := rows.Scan(&admin.Id, &admin.FullName, &age, &admin.UserName, &admin.Email, &admin.Password, &admin.Role, &admin.CreatedAt, &total); err != nil {
+			return nil, 0, err
+		}
+		admin.Age = age.Int64
+		admins = append(admins, &admin)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	if len(admins) == 0 && query.Offset() > 0 {
+		err = r.db.QueryRowContext(ctx, countStatement(where), args[:len(args)-2]...).Scan(&total)
+		if err != nil {
+			return nil, 0, err
+		}
+	}
+
+	return admins, total, nil
+}
+
+// ScrollAdmins returns query.Limit+1 admins from the position of after,
+// newest first, or oldest first when after reads backwards. A nil after
+// starts from the newest admin.
+func (r *adminRepo) ScrollAdmins(ctx context.Context, query listquery.Query, after *cursor.Cursor) (_ []*models.AdminReq, err error) {
+	ctx, span := startSpan(ctx, "SELECT admins")
+	defer func() { tracing.End(span, err) }()
+
+	where, args := adminFilters(query)
+	order := "created_at DESC, id DESC"
+	if after != nil {
+		comparison := "<"
+		if after.Direction == cursor.Prev {
+			comparison, order = ">", "created_at, id"
+		}
+		args = append(args, after.CreatedAt, after.ID)
+		where = append(where, fmt.Sprintf("(created_at, id) %s ($%d, $%d)", comparison, len(args)-1, len(args)))
+	}
+
+	statement := `SELECT id, full_name, age, username, email, password, role, created_at FROM admins`
+	if len(where) > 0 {
+		statement += " WHERE " + strings.Join(where, " AND ")
+	}
+	args = append(args, query.Limit+1)
+	statement += fmt.Sprintf(" ORDER BY %s LIMIT $%d", order, len(args))
+
+	rows, err := r.db.QueryContext(ctx, statement, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var admins []*models.AdminReq
+	for rows.Next() {
+		var (
+			admin models.AdminReq
+			age   sql.NullInt64
+		)
+		if err := rows.Scan(&admin.Id, &admin.FullName, &age, &admin.UserName, &admin.Email, &admin.Password, &admin.Role, &admin.CreatedAt); err != nil {
+			return nil, err
+		}
+		admin.Age = age.Int64
+		admins = append(admins, &admin)
+	}
+
+	return admins, rows.Err()
+}
+
+// adminFilters turns the filters of query into conditions on whitelisted columns
+func adminFilters(query listquery.Query) ([]string, []interface{}) {
+	var (
+		where []string
+		args  []interface{}
+	)
+	for field, value := range query.Filters {
+		column, ok := adminColumns[field]
+		if !ok {
+			continue
+		}
+		args = append(args, value)
+		where = append(where, fmt.Sprintf("%s = $%d", column, len(args)))
+	}
+
+	return where, args
+}
+
+func countStatement(where []string) string {
+	statement := "SELECT COUNT(*) FROM admins"
+	if len(where) > 0 {
+		statement += " WHERE " + strings.Join(where, " AND ")
+	}
+	return statement
+}
+
+func startSpan(ctx context.Context, statement string) (context.Context, trace.Span) {
+	return tracer.Start(ctx, statement,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.system", "postgresql"),
+			attribute.String("db.statement", statement),
+		))
+}
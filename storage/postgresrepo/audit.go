@@ -0,0 +1,12 @@
+package postgresrepo
+
+import (
+	"context"
+	"myproject/admin-api-gateway/api/models"
+)
+
+type AuditStorageI interface {
+	Insert(ctx context.Context, entries []*models.AuditEntry) error
+	List(ctx context.Context, filter models.AuditFilter) (*models.ListAuditEntries, error)
+	Export(ctx context.Context, filter models.AuditFilter, fn func(*models.AuditEntry) error) error
+}
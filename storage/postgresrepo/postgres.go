@@ -3,6 +3,8 @@ package postgresrepo
 import (
 	"context"
 	"myproject/admin-api-gateway/api/models"
+	"myproject/admin-api-gateway/pkg/cursor"
+	"myproject/admin-api-gateway/pkg/listquery"
 )
 
 type AdminStorageI interface {
@@ -10,6 +12,8 @@ type AdminStorageI interface {
 	Delete(ctx context.Context, userName, password string) error
 	Check(ctx context.Context, userName string) (string, string, bool, error)
 	ListAdmins(ctx context.Context, req models.ListAdminReq) (*models.ListAdminsResp, error)
+	QueryAdmins(ctx context.Context, query listquery.Query) ([]*models.AdminReq, int64, error)
+	ScrollAdmins(ctx context.Context, query listquery.Query, after *cursor.Cursor) ([]*models.AdminReq, error)
 	GetAdmin(ctx context.Context, req models.GetAdminReq) (*models.AdminReq, error)
 	Update(ctx context.Context, adminReq *models.AdminUpdateReq) (*models.AdminReq, error)
 }
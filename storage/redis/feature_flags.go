@@ -0,0 +1,86 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"myproject/admin-api-gateway/api/models"
+	"myproject/admin-api-gateway/pkg/tracing"
+	"myproject/admin-api-gateway/storage/repo"
+	"sort"
+
+	rd "github.com/gomodule/redigo/redis"
+)
+
+// featureFlagsKey is a hash of flag name to the JSON encoded flag, shared by
+// every gateway instance
+const featureFlagsKey = "feature_flags"
+
+type featureFlags struct {
+	reds *rd.Pool
+}
+
+func NewFeatureFlags(rds *rd.Pool) repo.FeatureFlagsI {
+	return &featureFlags{reds: rds}
+}
+
+func (r *featureFlags) List(ctx context.Context) (flags []*models.FeatureFlag, err error) {
+	ctx, span := startSpan(ctx, "HGETALL")
+	defer func() { tracing.End(span, err) }()
+
+	conn, err := r.reds.GetContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	values, err := rd.StringMap(rd.DoContext(conn, ctx, "HGETALL", featureFlagsKey))
+	if err != nil {
+		return nil, err
+	}
+
+	flags = make([]*models.FeatureFlag, 0, len(values))
+	for _, value := range values {
+		var flag models.FeatureFlag
+		if err := json.Unmarshal([]byte(value), &flag); err != nil {
+			return nil, err
+		}
+		flags = append(flags, &flag)
+	}
+	sort.Slice(flags, func(i, j int) bool { return flags[i].Name < flags[j].Name })
+
+	return flags, nil
+}
+
+func (r *featureFlags) Set(ctx context.Context, flag *models.FeatureFlag) (err error) {
+	ctx, span := startSpan(ctx, "HSET")
+	defer func() { tracing.End(span, err) }()
+
+	value, err := json.Marshal(flag)
+	if err != nil {
+		return err
+	}
+
+	conn, err := r.reds.GetContext(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	_, err = rd.DoContext(conn, ctx, "HSET", featureFlagsKey, flag.Name, string(value))
+	return err
+}
+
+// Delete removes the flag and reports whether it existed
+func (r *featureFlags) Delete(ctx context.Context, name string) (deleted bool, err error) {
+	ctx, span := startSpan(ctx, "HDEL")
+	defer func() { tracing.End(span, err) }()
+
+	conn, err := r.reds.GetContext(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+
+	count, err := rd.Int(rd.DoContext(conn, ctx, "HDEL", featureFlagsKey, name))
+	return count > 0, err
+}
@@ -0,0 +1,71 @@
+package redis
+
+import (
+	"context"
+	"myproject/admin-api-gateway/pkg/tracing"
+	"myproject/admin-api-gateway/storage/repo"
+	"time"
+
+	rd "github.com/gomodule/redigo/redis"
+	"github.com/google/uuid"
+)
+
+// slidingWindowScript keeps a sorted set of request timestamps per key and
+// admits a request only while the set holds fewer than limit entries inside
+// the window. It returns {allowed, remaining, reset_ms}.
+var slidingWindowScript = rd.NewScript(1, `
+local key = KEYS[1]
+local now = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local limit = tonumber(ARGV[3])
+local member = ARGV[4]
+
+redis.call('ZREMRANGEBYSCORE', key, 0, now - window)
+local count = redis.call('ZCARD', key)
+local allowed = 0
+if count < limit then
+	redis.call('ZADD', key, now, member)
+	redis.call('PEXPIRE', key, window)
+	count = count + 1
+	allowed = 1
+end
+
+local reset = window
+local oldest = redis.call('ZRANGE', key, 0, 0, 'WITHSCORES')
+if oldest[2] then
+	reset = window - (now - tonumber(oldest[2]))
+end
+
+return {allowed, limit - count, reset}
+`)
+
+type rateLimiter struct {
+	reds *rd.Pool
+}
+
+func NewRateLimiter(rds *rd.Pool) repo.RateLimiterI {
+	return &rateLimiter{reds: rds}
+}
+
+func (r *rateLimiter) Allow(ctx context.Context, key string, limit int, window time.Duration) (result *repo.RateLimitResult, err error) {
+	ctx, span := startSpan(ctx, "EVALSHA")
+	defer func() { tracing.End(span, err) }()
+
+	conn, err := r.reds.GetContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	now := time.Now().UnixMilli()
+	values, err := rd.Int64s(slidingWindowScript.Do(conn, key, now, window.Milliseconds(), limit, uuid.NewString()))
+	if err != nil {
+		return nil, err
+	}
+
+	return &repo.RateLimitResult{
+		Allowed:   values[0] == 1,
+		Remaining: int(values[1]),
+		Reset:     time.Duration(values[2]) * time.Millisecond,
+	}, nil
+}
@@ -1,11 +1,18 @@
 package redis
 
 import (
+	"context"
+	"myproject/admin-api-gateway/pkg/tracing"
 	"myproject/admin-api-gateway/storage/repo"
 
 	rd "github.com/gomodule/redigo/redis"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
+var tracer = otel.Tracer("myproject/admin-api-gateway/storage/redis")
+
 type redisRepo struct {
 	reds *rd.Pool
 }
@@ -14,25 +21,136 @@ func NewRedisRepo(rds *rd.Pool) repo.InMemoryStorageI {
 	return &redisRepo{reds: rds}
 }
 
-func (r *redisRepo) Set(key, value string) (err error) {
-	conn := r.reds.Get()
+func (r *redisRepo) Set(ctx context.Context, key, value string) (err error) {
+	ctx, span := startSpan(ctx, "SET")
+	defer func() { tracing.End(span, err) }()
+
+	conn, err := r.reds.GetContext(ctx)
+	if err != nil {
+		return err
+	}
 	defer conn.Close()
 
-	_, err = conn.Do("SET", key, value)
+	_, err = rd.DoContext(conn, ctx, "SET", key, value)
 	return err
 }
 
-func (r *redisRepo) SetWithTTL(key, value string, seconds int) (err error) {
-	conn := r.reds.Get()
+func (r *redisRepo) SetWithTTL(ctx context.Context, key, value string, seconds int) (err error) {
+	ctx, span := startSpan(ctx, "SETEX")
+	defer func() { tracing.End(span, err) }()
+
+	conn, err := r.reds.GetContext(ctx)
+	if err != nil {
+		return err
+	}
 	defer conn.Close()
 
-	_, err = conn.Do("SETEX", key, seconds, value)
+	_, err = rd.DoContext(conn, ctx, "SETEX", key, seconds, value)
 	return err
 }
 
-func (r *redisRepo) Get(key string) (interface{}, error) {
-	conn := r.reds.Get()
+func (r *redisRepo) Get(ctx context.Context, key string) (value interface{}, err error) {
+	ctx, span := startSpan(ctx, "GET")
+	defer func() { tracing.End(span, err) }()
+
+	conn, err := r.reds.GetContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	return rd.DoContext(conn, ctx, "GET", key)
+}
+
+// SetNX sets the key with a TTL only if it does not exist yet and reports whether it was set
+func (r *redisRepo) SetNX(ctx context.Context, key, value string, seconds int) (ok bool, err error) {
+	ctx, span := startSpan(ctx, "SET NX")
+	defer func() { tracing.End(span, err) }()
+
+	conn, err := r.reds.GetContext(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+
+	reply, err := rd.String(rd.DoContext(conn, ctx, "SET", key, value, "EX", seconds, "NX"))
+	if err == rd.ErrNil {
+		return false, nil
+	}
+
+	return reply == "OK", err
+}
+
+func (r *redisRepo) Del(ctx context.Context, key string) (err error) {
+	ctx, span := startSpan(ctx, "DEL")
+	defer func() { tracing.End(span, err) }()
+
+	conn, err := r.reds.GetContext(ctx)
+	if err != nil {
+		return err
+	}
 	defer conn.Close()
 
-	return conn.Do("GET", key)
+	_, err = rd.DoContext(conn, ctx, "DEL", key)
+	return err
+}
+
+// delIfEqualsScript deletes KEYS[1] only while it holds ARGV[1]
+var delIfEqualsScript = rd.NewScript(1, `
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('DEL', KEYS[1])
+end
+return 0
+`)
+
+// DelIfEquals deletes the key only if it still holds value and reports
+// whether it was deleted, so a lock is only released by its owner
+func (r *redisRepo) DelIfEquals(ctx context.Context, key, value string) (ok bool, err error) {
+	ctx, span := startSpan(ctx, "EVALSHA")
+	defer func() { tracing.End(span, err) }()
+
+	conn, err := r.reds.GetContext(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+
+	deleted, err := rd.Int(delIfEqualsScript.Do(conn, key, value))
+	return deleted == 1, err
+}
+
+func (r *redisRepo) Incr(ctx context.Context, key string) (value int64, err error) {
+	ctx, span := startSpan(ctx, "INCR")
+	defer func() { tracing.End(span, err) }()
+
+	conn, err := r.reds.GetContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+
+	return rd.Int64(rd.DoContext(conn, ctx, "INCR", key))
+}
+
+func (r *redisRepo) Ping(ctx context.Context) (err error) {
+	ctx, span := startSpan(ctx, "PING")
+	defer func() { tracing.End(span, err) }()
+
+	conn, err := r.reds.GetContext(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	_, err = rd.DoContext(conn, ctx, "PING")
+	return err
+}
+
+func startSpan(ctx context.Context, command string) (context.Context, trace.Span) {
+	return tracer.Start(ctx, "redis "+command,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.system", "redis"),
+			attribute.String("db.operation", command),
+		))
 }
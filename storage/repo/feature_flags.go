@@ -0,0 +1,12 @@
+package repo
+
+import (
+	"context"
+	"myproject/admin-api-gateway/api/models"
+)
+
+type FeatureFlagsI interface {
+	List(ctx context.Context) ([]*models.FeatureFlag, error)
+	Set(ctx context.Context, flag *models.FeatureFlag) error
+	Delete(ctx context.Context, name string) (bool, error)
+}
@@ -1,7 +1,14 @@
 package repo
 
+import "context"
+
 type InMemoryStorageI interface {
-	Set(key, value string) error
-	SetWithTTL(key, value string, seconds int) error
-	Get(key string) (interface{}, error)
-}
\ No newline at end of file
+	Set(ctx context.Context, key, value string) error
+	SetWithTTL(ctx context.Context, key, value string, seconds int) error
+	Get(ctx context.Context, key string) (interface{}, error)
+	SetNX(ctx context.Context, key, value string, seconds int) (bool, error)
+	Del(ctx context.Context, key string) error
+	DelIfEquals(ctx context.Context, key, value string) (bool, error)
+	Incr(ctx context.Context, key string) (int64, error)
+	Ping(ctx context.Context) error
+}
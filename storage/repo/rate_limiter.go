@@ -0,0 +1,16 @@
+package repo
+
+import (
+	"context"
+	"time"
+)
+
+type RateLimiterI interface {
+	Allow(ctx context.Context, key string, limit int, window time.Duration) (*RateLimitResult, error)
+}
+
+type RateLimitResult struct {
+	Allowed   bool
+	Remaining int
+	Reset     time.Duration
+}